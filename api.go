@@ -1,6 +1,9 @@
 package apigen
 
-import "go/ast"
+import (
+	"go/ast"
+	"go/token"
+)
 
 var (
 	//ast definition of an *js.Object often use in apigen
@@ -12,27 +15,100 @@ var (
 	}
 )
 
+//EmptyInterface returns the ast definition of interface{}
+//
+// braces have a (fake) position, so that it's printed on a single line.
+func EmptyInterface() *ast.InterfaceType {
+	return &ast.InterfaceType{
+		Methods: &ast.FieldList{Opening: token.Pos(1), Closing: token.Pos(1)},
+	}
+}
+
 //Api type holds a whole api.
 type Api struct {
-	Name    string   // package name
-	Imports []string // imports list of imports
-	Types   []*Type  // list of all types to be defined
-	Funcs   []*Func  // all funcs (methods and funcs)
+	Generator string   // generator name, for the "Code generated" header (default to "apigen")
+	Name      string   // package name
+	Imports   []string // imports list of imports
+	Consts    []*Const // constants, declared in a single block
+	Vars      []*Var   // variables, declared in a single block
+	Types     []*Type  // list of all types to be defined
+	Unions    []*Union // sum types, used as result types
+	Funcs     []*Func  // all funcs (methods and funcs)
+
+	// optional, declares an interface of the methods of each type, and asserts it (see InterfaceDecls)
+	Interfaces bool
+
+	// optional, the import path of the js package among the Imports, replaced by TargetSource (default to GopherJS.Import)
+	JSImport string
 }
 
 type Type struct {
-	Name       string
-	Properties []*Property
+	Description string // free text documentation (see Doc)
+	Name        string
+	Properties  []*Property
+	Parents     []*Type      // types embedded after the *js.Object, their methods are inherited
+	Constructor *Constructor // js constructor, nil if the type cannot be created from go
+	Exported    bool         // exports the wrapper: WrapFoo(*js.Object) instead of newFoo
+
+	handCtor bool // the ctor is hand written (see Suppress)
+}
+
+//Constructor describes how to create a new js object of a given type
+type Constructor struct {
+	Description string         // free text documentation (see Doc)
+	Name        string         // go func name (default to "New" + type name)
+	JS          string         // js constructor path, from the global object ("Foo", or "foo.Bar")
+	Params      *ast.FieldList // constructor arguments
+}
+
+//Union is a sum type: a sealed interface implemented by one type per alternative.
+//
+// The actual alternative of a js value is chosen at runtime, from its js typeof: alternatives
+// must have distinct TypeOf.
+type Union struct {
+	Description  string // free text documentation (see Doc)
+	Name         string // interface name
+	Alternatives []*Alternative
+}
+
+//Alternative is one of the types of a Union
+//
+// alternatives can be shared by several unions, they are declared once.
+type Alternative struct {
+	Description string                  // free text documentation (see Doc)
+	Name        string                  // go type implementing the union
+	Type        ast.Expr                // underlying type, to declare Name (nil if Name is declared elsewhere)
+	TypeOf      string                  // js typeof for this alternative ("string", "number", "boolean", "object", "function")
+	Convert     func(ast.Expr) ast.Expr // turns the *js.Object into a value of type Name
+}
+
+//Const is a named constant.
+type Const struct {
+	Description string   // free text documentation (see Doc)
+	Name        string   // constant name
+	Value       ast.Expr // constant value
+}
+
+//Var is a package variable.
+type Var struct {
+	Description string   // free text documentation (see Doc)
+	Name        string   // variable name
+	Value       ast.Expr // initial value
+
+	// optional, the var is declared as a func returning its Value (a *js.Object), evaluated at each call:
+	// for globals that might be defined after the package init (like a js library loaded later)
+	Lazy bool
 }
 
 type Property struct {
-	Name string   // property name
-	Type ast.Expr //expression defining a type
-	JS   string   // name in js
+	Description string   // free text documentation (see Doc)
+	Name        string   // property name
+	Type        ast.Expr //expression defining a type
+	JS          string   // name in js
 }
 
 type Func struct {
-	Description  string
+	Description  string                  // free text documentation (see Doc)
 	ReceiverType ast.Expr                //type expr or nil if it's not a method
 	ReceiverName string                  // either the receiver local name, or a global name to be used to make the call
 	Name         string                  // function anme
@@ -40,4 +116,22 @@ type Func struct {
 	Params       *ast.FieldList          // arguments
 	ResultType   ast.Expr                // result type
 	Convert      func(ast.Expr) ast.Expr // a function that turn the call expression ( *js.Object) into the return type.
+
+	// js returns 'this': the call result is ignored, and the receiver is returned instead (no Convert)
+	ReturnsReceiver bool
+
+	// optional, one per param: a function that turns the param into the argument passed to js (nil to pass it as is).
+	// a converted variadic param is not spread, it is passed as a single js array.
+	ConvertArgs []func(ast.Expr) ast.Expr
+
+	// optional, the documentation categories of the func (like "attributes"), they are not generated (see ComputeStats)
+	Categories []string
+
+	// optional, the url of the reference documentation of the js func (see Reference)
+	URL string
+
+	// optional, why or since when the func is deprecated ("since jQuery 3.5")
+	Deprecated string
+
+	lazyReceiver bool // the receiver is a Lazy var, called to get the object (see File)
 }
@@ -0,0 +1,161 @@
+package apijquery
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Accessor classifies the signatures of a getter/setter family
+type Accessor int
+
+const (
+	AccessorOther      Accessor = iota // not part of a getter/setter family
+	AccessorGetter                     // Attr(name) string
+	AccessorSetter                     // SetAttr(name, value)
+	AccessorMapSetter                  // SetAttrMap(map)
+	AccessorFuncSetter                 // SetAttrFunc(fn)
+)
+
+func (a Accessor) String() string {
+	switch a {
+	case AccessorGetter:
+		return "getter"
+	case AccessorSetter:
+		return "setter"
+	case AccessorMapSetter:
+		return "map setter"
+	case AccessorFuncSetter:
+		return "func setter"
+	default:
+		return "other"
+	}
+}
+
+//MarshalText encodes the accessor by its name, so it can be a json key
+func (a Accessor) MarshalText() ([]byte, error) { return []byte(a.String()), nil }
+
+//goName returns the go name of the accessor for the js 'name'
+func (a Accessor) goName(name string) string {
+	switch a {
+	case AccessorSetter:
+		return "Set" + GoName(name)
+	case AccessorMapSetter:
+		return "Set" + GoName(name) + "Map"
+	case AccessorFuncSetter:
+		return "Set" + GoName(name) + "Func"
+	default:
+		return GoName(name)
+	}
+}
+
+//mapType is the jquery doc type used for the argument of map setters
+const mapType = "map[string]interface{}"
+
+//classify returns the kind of accessor of a single signature
+//
+// setters return the receiver (jQuery), the kind of setter depends on its last argument
+// (a function, or a single plain object), getters return anything else.
+func classify(e *Entry, s Signature) Accessor {
+	if e.Return != "jQuery" {
+		return AccessorGetter
+	}
+	n := len(s.Argument)
+	switch {
+	case n == 0:
+		return AccessorOther
+	case s.Argument[n-1].Type == "Function":
+		return AccessorFuncSetter
+	case n == 1 && (s.Argument[0].Type == "PlainObject" || s.Argument[0].Type == "Object"):
+		return AccessorMapSetter
+	default:
+		return AccessorSetter
+	}
+}
+
+//Classification reports how a group of entries has been classified
+type Classification struct {
+	Name       string                `json:"name"`       // js name of the group
+	Family     bool                  `json:"family"`     // the group is split into a getter/setter family
+	Reason     string                `json:"reason"`     // why the group is, or is not, a family
+	Signatures map[Accessor][]string `json:"signatures"` // signatures by accessor kind, like "(name, value)"
+}
+
+func (c Classification) String() string {
+	parts := make([]string, 0, 4)
+	for _, a := range []Accessor{AccessorGetter, AccessorSetter, AccessorMapSetter, AccessorFuncSetter, AccessorOther} {
+		for _, s := range c.Signatures[a] {
+			parts = append(parts, a.String()+s)
+		}
+	}
+	return fmt.Sprintf("%v (%v): %v", c.Name, c.Reason, strings.Join(parts, " "))
+}
+
+//accessors splits a group of entries into getter/setter families.
+//
+// every entry is split by kind of accessor, and renamed accordingly: Attr(name) string,
+// SetAttr(name, value), SetAttrMap(map), SetAttrFunc(fn).
+// A group without both a getter and a setter is not a family, and it's returned as is (ok is false),
+// the report tells why.
+func accessors(entries []*Entry) (out []*Entry, report Classification, ok bool) {
+	report = Classification{Signatures: make(map[Accessor][]string)}
+
+	// classify every signature
+	classes := make([]map[Accessor][]Signature, len(entries))
+	for i, e := range entries {
+		report.Name = e.RawName
+		classes[i] = make(map[Accessor][]Signature)
+		for _, s := range e.Signature {
+			a := classify(e, s)
+			classes[i][a] = append(classes[i][a], s)
+			report.Signatures[a] = append(report.Signatures[a], signatureString(s))
+		}
+	}
+	hasSetter := len(report.Signatures[AccessorSetter])+len(report.Signatures[AccessorMapSetter])+len(report.Signatures[AccessorFuncSetter]) > 0
+	switch {
+	case len(report.Signatures[AccessorGetter]) == 0:
+		report.Reason = "no getter, the entries are merged"
+		return entries, report, false
+	case !hasSetter:
+		report.Reason = "no setter, the entries are merged"
+		return entries, report, false
+	}
+	report.Family, report.Reason = true, "getter/setter family"
+
+	// one entry per accessor kind
+	for i, e := range entries {
+		for _, a := range []Accessor{AccessorGetter, AccessorSetter, AccessorMapSetter, AccessorFuncSetter, AccessorOther} {
+			signatures := classes[i][a]
+			if len(signatures) == 0 {
+				continue
+			}
+			x := *e // shallow copy, signatures are replaced
+			x.Signature = signatures
+			if a == AccessorMapSetter {
+				x.Signature = []Signature{mapSignature(signatures[0])}
+			}
+			if a != AccessorOther {
+				x.SetGoName(a.goName(e.Name()))
+			}
+			out = append(out, &x)
+		}
+	}
+	return out, report, true
+}
+
+//mapSignature returns a copy of a map setter signature, with its argument typed as a go map
+func mapSignature(s Signature) Signature {
+	args := make([]Argument, len(s.Argument))
+	copy(args, s.Argument)
+	args[0].Type = mapType
+	s.Argument = args
+	return s
+}
+
+//signatureString returns the argument names of a signature, like "(name, value)"
+func signatureString(s Signature) string {
+	names := make([]string, len(s.Argument))
+	for i, a := range s.Argument {
+		names[i] = a.Name
+	}
+	return "(" + strings.Join(names, ", ") + ")"
+}
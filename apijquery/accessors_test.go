@@ -0,0 +1,45 @@
+package apijquery
+
+import "fmt"
+
+func ExampleAccessor() {
+	name := Argument{Name: "attributeName", Type: "String"}
+	entries := []*Entry{
+		&Entry{Type: "method", RawName: "attr", Return: "String", Signature: []Signature{
+			Signature{Argument: []Argument{name}},
+		}},
+		&Entry{Type: "method", RawName: "attr", Return: "jQuery", Signature: []Signature{
+			Signature{Argument: []Argument{name, Argument{Name: "value", Type: "String"}}},
+			Signature{Argument: []Argument{Argument{Name: "attributes", Type: "PlainObject"}}},
+			Signature{Argument: []Argument{name, Argument{Name: "function", Type: "Function"}}},
+		}},
+	}
+	out, report, _ := accessors(entries)
+	fmt.Println(report)
+	for _, e := range out {
+		fmt.Println(e.GoName(), signatureString(e.Signature[0]), e.Signature[0].Argument[0].Type)
+	}
+	//Output:
+	// attr (getter/setter family): getter(attributeName) setter(attributeName, value) map setter(attributes) func setter(attributeName, function)
+	// Attr (attributeName) String
+	// SetAttr (attributeName, value) String
+	// SetAttrMap (attributes) map[string]interface{}
+	// SetAttrFunc (attributeName, function) String
+}
+
+func ExampleClassification() {
+	entries := []*Entry{
+		&Entry{Type: "method", RawName: "load", Return: "jQuery", Signature: []Signature{
+			Signature{Argument: []Argument{Argument{Name: "url", Type: "String"}}},
+		}},
+		&Entry{Type: "method", RawName: "load", Return: "jQuery", Signature: []Signature{
+			Signature{Argument: []Argument{Argument{Name: "handler", Type: "Function"}}},
+		}},
+	}
+	out, report, ok := accessors(entries)
+	fmt.Println(report)
+	fmt.Println(len(out), ok)
+	//Output:
+	// load (no getter, the entries are merged): setter(url) func setter(handler)
+	// 2 false
+}
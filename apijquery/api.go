@@ -28,18 +28,39 @@ func NewApi() *Api {
 // <entries> <desc> and <entry>*
 // so to read "entries" I need a union type entryOrDesc, this is it
 type Entries struct {
-	Desc  string   `xml:"desc"`
+	Desc  Markup   `xml:"desc"`
 	Entry []*Entry `xml:"entry"`
 }
 
 //Argument describe a signature part
+//
+// it is also used for the <property> elements of a PlainObject argument
 type Argument struct {
-	Name       string `xml:"name,attr"`
-	Type       string `xml:"type,attr"`
-	Desc       string `xml:"desc"`
-	Optional   bool   `xml:"optional,attr"`
-	Deprecated string `xml:"deprecated,attr"`
-	Removed    string `xml:"removed,attr"`
+	Name       string     `xml:"name,attr"`
+	Type       string     `xml:"type,attr"`
+	Desc       Markup     `xml:"desc"`
+	Optional   bool       `xml:"optional,attr"`
+	Default    string     `xml:"default,attr"`
+	Added      string     `xml:"added,attr"`
+	Deprecated string     `xml:"deprecated,attr"`
+	Removed    string     `xml:"removed,attr"`
+	Arguments  []Argument `xml:"argument"` // callback arguments, when Type is a Function
+	Properties []Argument `xml:"property"` // object properties, when Type is a PlainObject (options)
+}
+
+//Category is a category the entry belongs to, like "events/mouse-events", or "version/1.7"
+type Category struct {
+	Slug string `xml:"slug,attr"`
+}
+
+//Note is an additional note about an entry
+//
+// most notes are only referenced by their id, their text is defined by the site templates.
+type Note struct {
+	ID    string `xml:"id,attr"`
+	Type  string `xml:"type,attr"`
+	Title string `xml:"data-title,attr"`
+	Text  Markup `xml:",innerxml"`
 }
 
 //Signature one of many possible signature for a single function
@@ -56,11 +77,17 @@ type Entry struct {
 	Removed    string      `xml:"removed,attr"`
 	RawName    string      `xml:"name,attr"`
 	Return     string      `xml:"return,attr"`
-	Title      string      `xml:"entry>title"`
-	Desc       string      `xml:"desc"`
+	Title      string      `xml:"title"`
+	Sample     string      `xml:"sample"` // usage sample, for selectors
+	Desc       Markup      `xml:"desc"`
+	LongDesc   Markup      `xml:"longdesc"`
 	Signature  []Signature `xml:"signature"`
+	Categories []Category  `xml:"category"`
+	Notes      []Note      `xml:"note"`
 
-	goName string // if empty GOName() uses a rule from Name() otherwise use this one
+	goName    string // if empty GOName() uses a rule from Name() otherwise use this one
+	groupDesc Markup // description of the <entries> this entry belongs to, if any
+	page      string // name of the page of the entry on the site, if it's been renamed
 }
 
 //Receiver computes the expected receiver
@@ -99,7 +126,61 @@ func (e Entry) GoName() string {
 	if e.goName != "" {
 		return e.goName
 	}
-	return Title(e.Name()) //Default behavior
+	return GoName(e.Name()) //Default behavior
+}
+
+//InCategory returns true if the entry belongs to the category 'slug', or to one of its sub categories
+func (e Entry) InCategory(slug string) bool {
+	for _, c := range e.Categories {
+		if c.Slug == slug || strings.HasPrefix(c.Slug, slug+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+//pseudoCategories are the slug prefixes of the categories that are not topics: the versions and deprecations
+var pseudoCategories = []string{"version/", "deprecated/"}
+
+//CategorySlugs returns the slugs of the topic categories of the entry (without "version/1.7", or "deprecated/deprecated-3.3")
+func (e Entry) CategorySlugs() []string {
+	slugs := make([]string, 0, len(e.Categories))
+	for _, c := range e.Categories {
+		pseudo := false
+		for _, prefix := range pseudoCategories {
+			pseudo = pseudo || strings.HasPrefix(c.Slug, prefix)
+		}
+		if !pseudo {
+			slugs = append(slugs, c.Slug)
+		}
+	}
+	return slugs
+}
+
+//URL returns the url of the entry on the api.jquery.com site
+func (e Entry) URL() string {
+	page := e.page
+	if page == "" {
+		page = e.RawName
+	}
+	return Site + "/" + page + "/"
+}
+
+//DeprecatedSince returns the deprecation notice of the entry ("" if it is not deprecated)
+func (e Entry) DeprecatedSince() string {
+	if e.Deprecated == "" {
+		return ""
+	}
+	return "since jQuery " + e.Deprecated
+}
+
+//Doc returns the godoc text for this entry: its description followed by the long description
+func (e Entry) Doc() string {
+	doc := e.Desc.Text()
+	if long := e.LongDesc.Text(); long != "" {
+		doc += "\n\n" + long
+	}
+	return doc
 }
 
 //Title is a function to uppercase the first letter of a name
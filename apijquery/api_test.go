@@ -0,0 +1,46 @@
+package apijquery
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+func ExampleEntry() {
+	src := `<entry type="method" name="on" return="jQuery">
+  <title>.on()</title>
+  <signature>
+    <added>1.7</added>
+    <argument name="events" type="String"><desc>Event types.</desc></argument>
+    <argument name="options" type="PlainObject" optional="true">
+      <property name="delay" type="Number" default="0"/>
+    </argument>
+    <argument name="handler" type="Function">
+      <argument name="eventObject" type="Event"/>
+    </argument>
+  </signature>
+  <desc>Attach an event handler.</desc>
+  <longdesc><p>More about it.</p></longdesc>
+  <note id="propagation-for-live-or-delegate" type="additional"/>
+  <category slug="events/event-handler-attachment"/>
+  <category slug="version/1.7"/>
+</entry>`
+	e := new(Entry)
+	if err := xml.Unmarshal([]byte(src), e); err != nil {
+		fmt.Println(err)
+		return
+	}
+	args := e.Signature[0].Argument
+	fmt.Println(e.Title, e.Doc())
+	fmt.Println(args[1].Properties[0].Name, args[1].Properties[0].Default)
+	fmt.Println(args[2].Arguments[0].Name, args[2].Arguments[0].Type)
+	fmt.Println(e.Notes[0].ID, e.InCategory("events"), e.InCategory("version/1.8"))
+	fmt.Println(e.CategorySlugs())
+	//Output:
+	// .on() Attach an event handler.
+	//
+	// More about it.
+	// delay 0
+	// eventObject Event
+	// propagation-for-live-or-delegate true false
+	// [events/event-handler-attachment]
+}
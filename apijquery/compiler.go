@@ -2,7 +2,6 @@ package apijquery
 
 import (
 	"fmt"
-	"log"
 	"sort"
 	"strings"
 
@@ -16,28 +15,52 @@ import (
 // *apigen.Api is a struct that can be generated into go source file.
 //
 //
-type Compiler struct{}
-
-//isOk return true if I have to keep the entry
-func (c Compiler) isOk(p *Entry) bool {
-	return p.Deprecated == "" && p.Removed == ""
+type Compiler struct {
+	Exported bool   // export the wrappers of the generated types: WrapFoo(*js.Object) instead of newFoo
+	Package  string // name of the generated package (default to "jquery")
+	Import   string // import path of the gopherjs "js" package, or of a compatible one (default to JSImport)
+	Global   string // js path of the jQuery global object (default to "jQuery")
+
+	Interfaces bool // declare the interface of the methods of each type (JQueryAPI)
+	Deprecated bool // keep the deprecated entries that are not removed yet, marked as deprecated
 }
 
-//logRejected just print out info about a rejected entry
-func (c Compiler) logRejected(p *Entry) {
-	if p.Removed != "" {
-		log.Printf("Skipping %v : removed=%v deprecated=%v", p.RawName, p.Removed, p.Deprecated)
-	} else {
-		log.Printf("Skipping %v : deprecated=%v", p.RawName, p.Deprecated)
+//JSImport is the default import path of the "js" package
+const JSImport = "github.com/gopherjs/gopherjs/js"
+
+//option returns 'value', or 'def' if it's empty
+func option(value, def string) string {
+	if value == "" {
+		return def
 	}
+	return value
+}
+
+//isOk return true if I have to keep the entry
+func (c Compiler) isOk(p *Entry) bool {
+	return p.Removed == "" && (p.Deprecated == "" || c.Deprecated)
 }
 
 //Compile the current jquery api into the independent apigen one
-func (c Compiler) Compile(api *Api) (out *apigen.Api, err error) {
+//
+// the report explains the decisions made along the way (skipped entries, merges, fallbacks...)
+func (c Compiler) Compile(api *Api) (out *apigen.Api, report *Report, err error) {
+	report = new(Report)
 
+	global := option(c.Global, "jQuery")
+	jsImport := option(c.Import, JSImport)
 	out = &apigen.Api{
-		Name:    "jquery",
-		Imports: []string{"github.com/gopherjs/gopherjs/js"},
+		Name:     option(c.Package, "jquery"),
+		Imports:  []string{jsImport},
+		JSImport: jsImport,
+		Consts:   eventConsts(api),
+		Vars: []*apigen.Var{&apigen.Var{
+			Description: "JQ returns the jQuery global object, the receiver of the static jQuery functions.\n\nIt is looked up at each call, so jQuery can be loaded after the package init.",
+			Name:        "JQ",
+			Value:       apigen.Global(global),
+			Lazy:        true,
+		}},
+		Interfaces: c.Interfaces,
 	}
 
 	//first collect all
@@ -47,7 +70,7 @@ func (c Compiler) Compile(api *Api) (out *apigen.Api, err error) {
 		if c.isOk(p) {
 			all = append(all, p)
 		} else {
-			c.logRejected(p)
+			report.skip(p)
 		}
 	}
 
@@ -57,9 +80,10 @@ func (c Compiler) Compile(api *Api) (out *apigen.Api, err error) {
 
 		for _, p := range e.Entry {
 			if c.isOk(p) {
+				p.groupDesc = e.Desc
 				entries = append(entries, p)
 			} else {
-				c.logRejected(p)
+				report.skip(p)
 			}
 		}
 		//entries might contains nothing, or only one
@@ -69,22 +93,60 @@ func (c Compiler) Compile(api *Api) (out *apigen.Api, err error) {
 		case len(entries) == 1:
 			//no possible conflict
 			all = append(all, entries[0])
-		case len(entries) == 2:
-			processSetter(entries[0], entries[1])
-			all = append(all, entries...)
+			if len(e.Entry) > 1 {
+				report.Accessors = append(report.Accessors, Classification{Name: entries[0].RawName, Reason: "single entry, the others are skipped"})
+			}
 		default:
-			all = append(all, entries...) // add all the merge will do it's job
+			// getter/setter families are split into accessors, the merge will do the rest
+			accs, classification, ok := accessors(entries)
+			report.Accessors = append(report.Accessors, classification)
+			if ok {
+				for _, a := range accs {
+					if a.GoName() != GoName(a.Name()) {
+						report.rename(a.RawName, a, "getter/setter family")
+					}
+				}
+			}
+			all = append(all, accs...)
 		}
 
 	}
 
 	// Now all "OK" entries are in "all" (getting rid of deprecated, and removed ones)
 
+	// some static entries are in fact the constructors of a type: they become its Constructor
+	constructors := make(map[string]*apigen.Constructor) // by jquery type name
+	for tyname, ctor := range typeConstructors {
+		constructors[tyname] = ctor
+	}
+	funcs := all[:0]
+	for _, e := range all {
+		tyname, exists := entryConstructors[e.RawName]
+		if !exists {
+			funcs = append(funcs, e)
+			continue
+		}
+		e.SetGoName("New" + GoName(tyname))
+		report.rename(e.RawName, e, "constructor of the "+GoName(tyname)+" type")
+		report.mergeSignatures(e)
+		report.fallbacks(e)
+		constructors[tyname] = &apigen.Constructor{
+			Description: fmt.Sprintf("%s creates a new %s, with %s.%s().\n\n%s", e.GoName(), GoName(tyname), global, e.Name(), e.Doc()),
+			JS:          e.Name(),
+			Params:      compileParams(e.Signature[0]),
+		}
+	}
+	all = funcs
+
 	//collect all types defined in the API, into a set of declared receivers
 	typenames := make(map[string]interface{}) // map of all types found
 	for _, e := range all {
 		typenames[e.Receiver()] = nil // this identify the type
 	}
+	// the types with a constructor are generated, even without entries of their own
+	for tyname := range constructors {
+		typenames[tyname] = nil
+	}
 
 	//Deal with EXCEPTIONS
 
@@ -93,20 +155,35 @@ func (c Compiler) Compile(api *Api) (out *apigen.Api, err error) {
 	delete(typenames, "jQuery.fx")
 	delete(typenames, "jQuery.fn")
 
-	for _, e := range all {
-		switch {
-		// rename Callbacks it was in fact a constructor, and it's name will collide with the Callbacks definition type
-		case e.RawName == "jQuery.Callbacks":
-			e.RawName = "jQuery.newCallbacks"
-
-			// rename Deferred it was in fact a constructor, and it's name will collide with the Deferred definition type
-		case e.RawName == "jQuery.Deferred":
-			e.RawName = "jQuery.newDeferred"
+	// some types have no entry of their own, but inherit from types that have
+	for child := range typeParents {
+		typenames[child] = nil
+	}
+	// and DOM elements are only returned by the other entries (like event.target)
+	typenames["Element"] = nil
+	generated := make(map[string]*apigen.Type) // generated types by their jquery name
+	unions := make(map[string]*apigen.Union)   // union result types by their go name
+	addUnion := func(ret string) {
+		if isUnion(ret) {
+			unions[unionName(ret)] = c.union(ret)
+		}
+	}
 
+	for _, e := range all {
+		rawName := e.RawName
+		if e.page == "" {
+			e.page = rawName
+		}
 		//move jquery.fn methods (only one right now) as prefixed Fn directly to jQuery
-		case strings.HasPrefix(e.RawName, "jQuery.fn"):
-			e.RawName = "jQuery.Fn" + Title(e.Name())
+		if strings.HasPrefix(e.RawName, "jQuery.fn") {
+			e.RawName = "jQuery.Fn" + GoName(e.Name())
+			report.rename(rawName, e, "jQuery.fn method")
 		}
+		// the doc is sometimes too vague about the return type
+		if r, exists := returnTypes[e.RawName]; exists {
+			e.Return = r
+		}
+		e.Return = canonicalReturn(e.Return)
 	}
 
 	// entries need to be sorted by name so the api generation has no "random" order
@@ -120,8 +197,6 @@ func (c Compiler) Compile(api *Api) (out *apigen.Api, err error) {
 
 	//for each type create a corresponding apigen type
 	for _, tyname := range names {
-		log.Printf("processing %v", tyname)
-
 		// keep methods and properties into a map (for key unicity)
 		// we'll sort the map later
 		methods := make(map[string]*Entry)
@@ -135,14 +210,17 @@ func (c Compiler) Compile(api *Api) (out *apigen.Api, err error) {
 				if was, exists := methods[e.GoName()]; exists {
 					//check that an entry with the same name not already exists (this is possible)
 					x := merge(was, e) // inplace merge
+					report.Merged = append(report.Merged, Merged{
+						Name:    was.RawName,
+						GoName:  x.GoName(),
+						Returns: []string{was.Return, e.Return},
+						Return:  x.Return,
+					})
 					// merge has the "permission" to change the entry name, before storing it
 					methods[x.GoName()] = x
 				} else {
 					methods[e.GoName()] = e
 				}
-				// in any case, entry can have "multiple" signature for the same function.
-				// in go we do not have this, so we need to fallback to the most generic interface (...interface{})
-				mergeSignatures(methods[e.GoName()])
 
 			case e.Type == "property":
 				if _, exists := properties[e.Name()]; exists {
@@ -162,8 +240,8 @@ func (c Compiler) Compile(api *Api) (out *apigen.Api, err error) {
 			gotypename = ""
 
 		//unsupported objects
-		case "event", "callbacks", "deferred": //supported objects
-			gotypename = Title(tyname)
+		case "event", "callbacks", "deferred", "jqXHR", "Element": //supported objects
+			gotypename = GoName(tyname)
 		}
 
 		// and build the correct apigen.Type.
@@ -173,11 +251,21 @@ func (c Compiler) Compile(api *Api) (out *apigen.Api, err error) {
 		if gotypename != "" { // this is a regular type
 
 			ty = &apigen.Type{
-				Name:       gotypename,
-				Properties: make([]*apigen.Property, 0, len(properties)),
+				Description: typeDescriptions[gotypename],
+				Name:        gotypename,
+				Properties:  make([]*apigen.Property, 0, len(properties)),
+				Exported:    c.Exported,
+			}
+			if ctor, exists := constructors[tyname]; exists {
+				ty.Constructor = &apigen.Constructor{
+					Description: ctor.Description,
+					Name:        ctor.Name,
+					JS:          global + "." + ctor.JS,
+					Params:      ctor.Params,
+				}
 			}
-			log.Printf("Compiling Type %v", gotypename)
 			out.Types = append(out.Types, ty)
+			generated[tyname] = ty
 
 			//sort by name and compile properties
 			names := make([]string, 0, len(properties))
@@ -188,15 +276,24 @@ func (c Compiler) Compile(api *Api) (out *apigen.Api, err error) {
 
 			for _, n := range names {
 				e := properties[n]
+				addUnion(e.Return)
+				report.fallbacks(e)
 				ty.Properties = append(ty.Properties, &apigen.Property{
-					Name: e.GoName(),       //string
-					JS:   e.Name(),         //string   // name in js
-					Type: goType(e.Return), //ast.Expr //expression defining a type
+					Description: e.Doc(),              //string
+					Name:        e.GoName(),           //string
+					JS:          e.Name(),             //string   // name in js
+					Type:        resultType(e.Return), //ast.Expr //expression defining a type
 				})
 
 			}
 		}
 
+		// the event methods have a typed signature of their own
+		var funcs []*apigen.Func
+		if tyname == "" {
+			funcs = c.eventFuncs(methods)
+		}
+
 		//sort by name and compile funcs
 		names := make([]string, 0, len(methods))
 		for k := range methods {
@@ -215,24 +312,124 @@ func (c Compiler) Compile(api *Api) (out *apigen.Api, err error) {
 			} else {
 				rname, rtype = "x", &ast.Ident{Name: gotypename}
 			}
-			//everything else is straightforward
+			// in any case, entry can have "multiple" signature for the same function.
+			// in go we do not have this, so we need to merge them, or to fallback to the most generic interface (...interface{})
 			e := methods[n]
-			out.Funcs = append(out.Funcs, &apigen.Func{
-				Description:  e.Desc,
+			shorthandHandler(e)
+			report.mergeSignatures(e)
+
+			//everything else is straightforward
+			addUnion(e.Return)
+			report.fallbacks(e)
+			funcs = append(funcs, &apigen.Func{
+				Description:  e.Doc(),
 				ReceiverType: rtype,
 				ReceiverName: rname,
 				Name:         e.GoName(),                    //    string
 				JS:           e.Name(),                      //    string
-				ResultType:   goType(e.Return),              //Expr          // field/method/parameter type
+				ResultType:   resultType(e.Return),          //Expr          // field/method/parameter type
 				Params:       compileParams(e.Signature[0]), //    *ast.FieldList
-				Convert:      converterFor(e.Return),        //    func(ast.Expr) ast.Expr //the expression that deals with types
+				Convert:      c.converterFor(e.Return),      //    func(ast.Expr) ast.Expr //the expression that deals with types
+				ConvertArgs:  c.convertArgs(e.Signature[0]),
+
+				ReturnsReceiver: returnsThis(e), // no need to wrap 'this' again
+				Categories:      e.CategorySlugs(),
+				URL:             e.URL(),
+				Deprecated:      e.DeprecatedSince(),
 			})
 		}
+		sort.Slice(funcs, func(i, j int) bool { return funcs[i].Name < funcs[j].Name })
+		out.Funcs = append(out.Funcs, funcs...)
+
+	}
+
+	//sort unions by name
+	for _, u := range unions {
+		out.Unions = append(out.Unions, u)
+	}
+	sort.Slice(out.Unions, func(i, j int) bool { return out.Unions[i].Name < out.Unions[j].Name })
 
+	//link every type to its parents (when both have been generated)
+	for child, parents := range typeParents {
+		for _, parent := range parents {
+			if ty, exists := generated[child]; exists && generated[parent] != nil {
+				ty.Parents = append(ty.Parents, generated[parent])
+			}
+		}
 	}
 	return
 }
 
+//typeParents declares the inheritance between jquery types: the child type embeds its parents.
+//
+// keys and values are the names used in the jquery documentation.
+var typeParents = map[string][]string{
+	"jqXHR": []string{"deferred"}, // jqXHR implements the Promise interface
+}
+
+//newSetMethods are the jQuery methods returning a new jQuery object, instead of 'this'
+var newSetMethods = map[string]bool{
+	"add": true, "addBack": true, "andSelf": true, "appendTo": true, "children": true,
+	"clone": true, "closest": true, "contents": true, "end": true, "eq": true, "even": true,
+	"filter": true, "find": true, "first": true, "has": true, "insertAfter": true,
+	"insertBefore": true, "last": true, "map": true, "next": true, "nextAll": true,
+	"nextUntil": true, "not": true, "odd": true, "offsetParent": true, "parent": true,
+	"parents": true, "parentsUntil": true, "prependTo": true, "prev": true, "prevAll": true,
+	"prevUntil": true, "pushStack": true, "replaceAll": true, "siblings": true, "slice": true,
+}
+
+//returnsThis returns true if the method returns its own receiver ('this') in js
+//
+// jQuery, Deferred and Callbacks methods returning their own type are chainable, they return 'this',
+// except for the jQuery methods that build a new set of elements (see newSetMethods)
+func returnsThis(e *Entry) bool {
+	switch e.Receiver() {
+	case "":
+		return e.Return == "jQuery" && !newSetMethods[e.Name()]
+	case "deferred":
+		return e.Return == "Deferred"
+	case "callbacks":
+		return e.Return == "Callbacks"
+	default:
+		return false
+	}
+}
+
+//returnTypes overrides the return type of some entries (by raw name)
+var returnTypes = map[string]string{
+	"event.which": "Integer", // a key or button code
+}
+
+//typeConstructors declares the js constructors of the jquery types (by their jquery name)
+//
+// their js path is relative to the jQuery global object. The constructors documented by an entry
+// are compiled from it (see entryConstructors).
+var typeConstructors = map[string]*apigen.Constructor{
+	"event": &apigen.Constructor{
+		Description: "NewEvent creates a new jQuery event object, to be triggered.\n\nSee " + Site + "/category/events/event-object/",
+		JS:          "Event",
+		Params:      &ast.FieldList{List: []*ast.Field{param("src", "string")}},
+	},
+}
+
+//entryConstructors maps the static entries that are in fact constructors to the jquery name of their type
+//
+// the entries have the name of the type: they cannot be generated as funcs.
+var entryConstructors = map[string]string{
+	"jQuery.Callbacks": "callbacks",
+	"jQuery.Deferred":  "deferred",
+}
+
+//typeDescriptions documents the generated types (they have no entry of their own)
+var typeDescriptions = map[string]string{
+	"JQuery":    "JQuery wraps a jQuery object: a set of matched DOM elements.\n\nSee " + Site + "/Types/#jQuery",
+	"Event":     "Event wraps a jQuery event object, normalized according to W3C standards.\n\nSee " + Site + "/category/events/event-object/",
+	"Callbacks": "Callbacks wraps a multi-purpose callbacks list object, as returned by jQuery.Callbacks().\n\nSee " + Site + "/jQuery.Callbacks/",
+	"JqXHR":     "JqXHR wraps the jQuery XMLHttpRequest object, as returned by jQuery.ajax(). It is a Deferred.\n\nSee " + Site + "/jQuery.ajax/#jqXHR",
+	"Deferred":  "Deferred wraps a chainable utility object, as returned by jQuery.Deferred().\n\nSee " + Site + "/category/deferred-object/",
+	"Element":   "Element wraps a DOM element, like the target of an event.\n\nSee " + Site + "/Types/#Element",
+}
+
 //compileParams returns the field list from a given signature
 func compileParams(s Signature) *ast.FieldList {
 	fields := make([]*ast.Field, 0, 10)
@@ -249,7 +446,7 @@ func compileParams(s Signature) *ast.FieldList {
 		}
 		fields = append(fields, &ast.Field{
 			Names: []*ast.Ident{
-				&ast.Ident{Name: escapeReservedWord(a.Name)},
+				&ast.Ident{Name: escapeReservedWord(GoLocalName(a.Name))},
 			},
 			Type: ty,
 		})
@@ -268,8 +465,20 @@ func compileParams(s Signature) *ast.FieldList {
 //
 //apigen has a bunch of ready to use converter, we just need to map them according
 // to the convention in jquery doc.
-func converterFor(name string) func(ast.Expr) ast.Expr {
+func (c Compiler) converterFor(name string) func(ast.Expr) ast.Expr {
+
+	if isUnion(name) {
+		return func(j ast.Expr) ast.Expr {
+			return &ast.CallExpr{
+				Fun:  &ast.Ident{Name: apigen.WrapperName(unionName(name), false)},
+				Args: []ast.Expr{j},
+			}
+		}
+	}
 
+	if canonicalTypes[name] == "String" { // the strings of the doc, like selector or htmlString
+		return apigen.StringConverter
+	}
 	switch name {
 
 	case "Boolean", "boolean":
@@ -281,22 +490,19 @@ func converterFor(name string) func(ast.Expr) ast.Expr {
 	case "Integer":
 		return apigen.IntConverter
 
-	case "String", "selector", "Selector":
-		return apigen.StringConverter
-
 	case "undefined", "":
 		return apigen.InterfaceConverter
 
 	//unsupported
-	case "Object", "jqXHR", "Function", "Promise", "Array", "XMLDocument", "Element":
+	case "Object", "Function", "Promise", "Array", "XMLDocument":
 		return apigen.IdentityConverter
 
-	case "jQuery", "Event", "Callbacks", "Deferred": //supported objects
+	case "jQuery", "Event", "Callbacks", "Deferred", "jqXHR", "Element": //supported objects
 
 		return func(j ast.Expr) ast.Expr {
 			return &ast.CallExpr{
 				Fun: &ast.Ident{
-					Name: fmt.Sprintf("new%s", Title(name)),
+					Name: apigen.WrapperName(GoName(name), c.Exported),
 				},
 				Args: []ast.Expr{j},
 				//Ellipsis: token.Pos(120),
@@ -324,46 +530,95 @@ func merge(o, n *Entry) *Entry {
 	if o.Return == n.Return {
 		mreturn = o.Return
 	} else {
-		log.Printf("merging return type for %v (%v <> %v)", o.RawName, o.Return, n.Return)
-		mreturn = "Object" // by default
+		mreturn = canonicalReturn(o.Return + unionSeparator + n.Return) // "Object" if it is not a possible union
+	}
 
+	//deal with descriptions: entries from the same group share the group description
+	desc := o.Desc + "\n<p>OR</p>\n" + n.Desc
+	if o.groupDesc != "" && o.groupDesc == n.groupDesc {
+		desc = o.groupDesc
 	}
 
 	return &Entry{
-		Type:      o.Type, // they must have the same type
-		RawName:   o.RawName,
-		Return:    mreturn,
-		Desc:      o.Desc + "\n// OR\n// " + n.Desc,
-		Signature: append(append(make([]Signature, 0, 10), o.Signature...), n.Signature...),
+		Type:       o.Type, // they must have the same type
+		RawName:    o.RawName,
+		Return:     mreturn,
+		Desc:       desc,
+		LongDesc:   o.LongDesc + "\n" + n.LongDesc,
+		Signature:  append(append(make([]Signature, 0, 10), o.Signature...), n.Signature...),
+		Categories: mergeCategories(o.Categories, n.Categories),
+		Notes:      append(append(make([]Note, 0, len(o.Notes)+len(n.Notes)), o.Notes...), n.Notes...),
+		goName:     o.goName, // they have the same go name (like SetAttr), or none
+		groupDesc:  o.groupDesc,
+		page:       o.page,
 	}
 
 }
 
-func mergeSignatures(x *Entry) {
-	//s is the post generic signature
-	s := Signature{
-		Argument: []Argument{
-			Argument{
-				Name: "i",
-				Type: "interface{}",
-			},
-		},
-		Variadic: true,
+//mergeCategories returns the categories of both, without duplicates
+func mergeCategories(o, n []Category) []Category {
+	merged := append(make([]Category, 0, len(o)+len(n)), o...)
+	for _, c := range n {
+		found := false
+		for _, m := range merged {
+			found = found || m == c
+		}
+		if !found {
+			merged = append(merged, c)
+		}
 	}
+	return merged
+}
 
-	//TODO: sometimes you don't need to be that violent
-	if len(x.Signature) > 1 {
-		x.Signature = []Signature{s}
+//mergeSignatures merges the signatures of 'x' into a single one, when go cannot express them, and records it.
+//
+// signatures of the same arity are merged argument by argument: the arguments that differ are widened to
+// interface{}. Signatures of different arities, or with optional arguments, are collapsed to a single
+// ...interface{} argument.
+func (r *Report) mergeSignatures(x *Entry) {
+	var signatures []string
+	for _, sig := range x.Signature {
+		signatures = append(signatures, signatureString(sig))
+	}
+	collapse := func(reason string) {
+		x.Signature = []Signature{Signature{Argument: []Argument{Argument{Name: "i", Type: "interface{}"}}, Variadic: true}}
+		r.Variadic = append(r.Variadic, Variadic{Name: x.RawName, GoName: x.GoName(), Signatures: signatures, Reason: reason})
 	}
 
+	for _, sig := range x.Signature[1:] {
+		if len(sig.Argument) != len(x.Signature[0].Argument) {
+			collapse("several signatures")
+			return
+		}
+	}
 	// if an argument is optional it need to be a generic variadic interface
-	for _, a := range x.Signature[0].Argument {
-		if a.Optional {
-			x.Signature = []Signature{s}
-			break
+	for _, sig := range x.Signature {
+		for _, a := range sig.Argument {
+			if a.Optional {
+				collapse("optional arguments")
+				return
+			}
 		}
 	}
+	if len(x.Signature) == 1 {
+		return
+	}
 
+	// same arity: the first signature, with the differing arguments widened
+	args := make([]Argument, len(x.Signature[0].Argument))
+	copy(args, x.Signature[0].Argument)
+	var widened []string
+	for i := range args {
+		for _, sig := range x.Signature[1:] {
+			if sig.Argument[i].Type != args[i].Type {
+				args[i].Type = "interface{}"
+				widened = append(widened, args[i].Name)
+				break
+			}
+		}
+	}
+	x.Signature = []Signature{Signature{Argument: args}}
+	r.Widened = append(r.Widened, Widened{Name: x.RawName, GoName: x.GoName(), Signatures: signatures, Arguments: widened})
 }
 
 var reservedWords = map[string]interface{}{
@@ -408,6 +663,22 @@ func escapeReservedWord(word string) string {
 	return word
 }
 
+//isFallback returns true if the jquery type 's' has no go equivalent, and is generated as *js.Object
+func isFallback(s string) bool {
+	_, ok := goType(s).(*ast.StarExpr)
+	return ok
+}
+
+//resultType returns the go type of a result of the jquery type 's': the union type of a union, the goType otherwise
+//
+// the unions are declared from the results only (see Compile).
+func resultType(s string) ast.Expr {
+	if isUnion(s) {
+		return &ast.Ident{Name: unionName(s)}
+	}
+	return goType(s)
+}
+
 //goType return the ast.Expr defining the golang type for the jquery declared type
 func goType(s string) (t ast.Expr) {
 	// defer func() {
@@ -416,14 +687,30 @@ func goType(s string) (t ast.Expr) {
 	//s is the real receiver described in the entry file
 	// "" for JQuery
 	// jQuery for nil type etc
+	if isUnion(s) {
+		// an argument accepts a go value of any of the alternatives (only results are unions)
+		return apigen.EmptyInterface()
+	}
+	if canonicalTypes[s] == "String" { // the strings of the doc, like selector or htmlString
+		return &ast.Ident{Name: "string"}
+	}
 	switch s {
 	case "", "undefined", "interface{}":
-		return &ast.InterfaceType{
-			Methods: &ast.FieldList{},
-		}
+		return apigen.EmptyInterface()
 	case "jQuery":
 		return &ast.Ident{Name: "JQuery"}
 
+	case mapType:
+		return &ast.MapType{
+			Key:   &ast.Ident{Name: "string"},
+			Value: apigen.EmptyInterface(),
+		}
+
+	case handlerType:
+		return &ast.FuncType{
+			Params: &ast.FieldList{List: []*ast.Field{&ast.Field{Type: &ast.Ident{Name: "Event"}}}},
+		}
+
 	case "Boolean", "boolean":
 		return &ast.Ident{Name: "bool"}
 
@@ -433,12 +720,9 @@ func goType(s string) (t ast.Expr) {
 	case "Integer":
 		return &ast.Ident{Name: "int"}
 
-	case "String", "selector", "Selector":
-		return &ast.Ident{Name: "string"}
-
 	//unsupported objects
-	case "event", "callbacks", "deferred", "Event", "Callbacks", "Deferred": //supported objects
-		return &ast.Ident{Name: Title(s)}
+	case "event", "callbacks", "deferred", "jqXHR", "Event", "Callbacks", "Deferred", "Element": //supported objects
+		return &ast.Ident{Name: GoName(s)}
 
 	default:
 		//case "Object", "jqXHR", "Function", "Promise", "Array", "XMLDocument", "Element", "PlainObject", "Anything":
@@ -451,22 +735,3 @@ func goType(s string) (t ast.Expr) {
 		//	panic(fmt.Errorf("unknown type %s", s))
 	}
 }
-
-func processSetter(get, set *Entry) {
-	// get and set might be in no particular order
-	//
-	// check that we are in a get set case, and that get/set are in the right order
-	if get.Return == "jQuery" {
-		if set.Return == "jQuery" {
-			return // nothing to do, this is not a get/set case
-		}
-		processSetter(set, get) // call in reverse order (because it was not)
-		return
-	}
-	if set.Return == "jQuery" {
-		// ok, get and set have different return type
-		// set has the right return type
-		set.SetGoName("Set" + Title(set.Name()))
-		log.Printf("renaming %v %v -> %v", set.RawName, set.Return, set.GoName())
-	}
-}
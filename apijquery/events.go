@@ -0,0 +1,200 @@
+package apijquery
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+
+	"github.com/ericaro/apigen"
+)
+
+//eventCategories are the categories of the shorthand methods named after the event they bind (.click(handler))
+var eventCategories = []string{"events/browser-events", "events/form-events", "events/keyboard-events", "events/mouse-events"}
+
+//eventNames returns the names of the events documented by the entries, sorted.
+//
+// they are the names of the shorthand methods of the event categories, taking a handler. The
+// deprecated shorthand methods still name an event, only the removed ones are ignored.
+func eventNames(api *Api) []string {
+	all := append(make([]*Entry, 0, len(api.Entry)), api.Entry...)
+	for _, e := range api.Entries {
+		all = append(all, e.Entry...)
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, e := range all {
+		if e.Type != "method" || e.Removed != "" || e.Receiver() != "" || seen[e.Name()] || !isShorthand(e) {
+			continue
+		}
+		seen[e.Name()] = true
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+//isShorthand returns true if 'e' is in an event category, and binds a handler
+func isShorthand(e *Entry) bool {
+	in := false
+	for _, c := range eventCategories {
+		in = in || e.InCategory(c)
+	}
+	if !in {
+		return false
+	}
+	for _, s := range e.Signature {
+		for _, a := range s.Argument {
+			if a.Name == "handler" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//handlerType is the type of the event handlers, the handler of the shorthand methods is typed as such (see shorthandHandler)
+const handlerType = "func(Event)"
+
+//shorthandHandler types the handler of the shorthand method 'e' as a func(Event), like the handler of On
+func shorthandHandler(e *Entry) {
+	if e.Type != "method" || e.Receiver() != "" || !isShorthand(e) {
+		return
+	}
+	for i, s := range e.Signature {
+		args := make([]Argument, len(s.Argument)) // the arguments may be shared with another entry
+		copy(args, s.Argument)
+		for j, a := range args {
+			if a.Name == "handler" && a.Type == "Function" {
+				args[j].Type = handlerType
+			}
+		}
+		e.Signature[i].Argument = args
+	}
+}
+
+//eventConstName returns the name of the constant of the event 'name' ("mouseenter" -> EventMouseEnter),
+// named after its shorthand method (see Overrides)
+func eventConstName(name string) string { return "Event" + GoName(name) }
+
+//eventConsts returns the constants for the event names documented by the entries, sorted by name
+func eventConsts(api *Api) []*apigen.Const {
+	names := eventNames(api)
+	consts := make([]*apigen.Const, 0, len(names))
+	for _, name := range names {
+		consts = append(consts, &apigen.Const{
+			Description: eventConstName(name) + " is the name of the " + strconv.Quote(name) + " event.",
+			Name:        eventConstName(name),
+			Value:       &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(name)},
+		})
+	}
+	return consts
+}
+
+//eventFuncs replaces the generic "on", "one", "off" and "trigger" methods of JQuery by a typed event api.
+//
+// their entries are removed from 'methods', and the typed funcs are returned instead.
+func (c Compiler) eventFuncs(methods map[string]*Entry) (funcs []*apigen.Func) {
+	events := param("events", "string")
+	selector := param("selector", "string")
+	handler := &ast.Field{
+		Names: []*ast.Ident{&ast.Ident{Name: "handler"}},
+		Type:  goType(handlerType),
+	}
+	extra := &ast.Field{
+		Names: []*ast.Ident{&ast.Ident{Name: "extraParameters"}},
+		Type:  &ast.Ellipsis{Elt: apigen.EmptyInterface()},
+	}
+
+	method := func(e *Entry, name, desc string, params ...*ast.Field) *apigen.Func {
+		f := &apigen.Func{
+			Description:  desc,
+			ReceiverType: &ast.Ident{Name: "JQuery"},
+			ReceiverName: "x",
+			Name:         name,
+			JS:           e.Name(),
+			Params:       &ast.FieldList{List: params},
+			ResultType:   goType("jQuery"),
+			Convert:      c.converterFor("jQuery"),
+			ConvertArgs:  make([]func(ast.Expr) ast.Expr, len(params)),
+
+			ReturnsReceiver: true,
+			Categories:      e.CategorySlugs(),
+			URL:             e.URL(),
+			Deprecated:      e.DeprecatedSince(),
+		}
+		for i, p := range params {
+			switch p {
+			case handler:
+				f.ConvertArgs[i] = c.handlerConverter
+			case extra:
+				f.ConvertArgs[i] = apigen.IdentityConverter // a single array, not spread
+			}
+		}
+		return f
+	}
+	delegated := "\n\nThe handler is called only for the descendants of the selected elements that match the selector."
+
+	for _, name := range []string{"on", "one"} {
+		if e, exists := methods[GoName(name)]; exists {
+			delete(methods, GoName(name))
+			funcs = append(funcs,
+				method(e, GoName(name), e.Desc.Text(), events, handler),
+				method(e, GoName(name)+"Delegated", e.Desc.Text()+delegated, events, selector, handler),
+			)
+		}
+	}
+	if e, exists := methods["Off"]; exists {
+		delete(methods, "Off")
+		// the js function of a go handler is created by each call: Off cannot find it again
+		off := "\n\nIt removes all the handlers of the events, the go handlers cannot be told apart: " +
+			"name the events with a namespace, like \"click.myPlugin\", to remove only some of them."
+		funcs = append(funcs,
+			method(e, "Off", e.Desc.Text()+off, events),
+			method(e, "OffDelegated", e.Desc.Text()+"\n\nIt removes the handlers delegated with the selector."+off, events, selector),
+		)
+	}
+	if e, exists := methods["Trigger"]; exists {
+		delete(methods, "Trigger")
+		desc := e.Desc.Text() + "\n\nThe extra parameters are passed along to the handlers, after the event."
+		funcs = append(funcs, method(e, "Trigger", desc, param("eventType", "string"), extra))
+	}
+	return
+}
+
+//param returns a single named parameter
+func param(name, ty string) *ast.Field {
+	return &ast.Field{
+		Names: []*ast.Ident{&ast.Ident{Name: name}},
+		Type:  &ast.Ident{Name: ty},
+	}
+}
+
+//convertArgs returns the converters of the arguments of 's', nil if none needs one
+func (c Compiler) convertArgs(s Signature) (converts []func(ast.Expr) ast.Expr) {
+	for i, a := range s.Argument {
+		if a.Type == handlerType {
+			if converts == nil {
+				converts = make([]func(ast.Expr) ast.Expr, len(s.Argument))
+			}
+			converts[i] = c.handlerConverter
+		}
+	}
+	return
+}
+
+//handlerConverter wraps a func(Event) handler into a func(*js.Object) for js
+func (c Compiler) handlerConverter(h ast.Expr) ast.Expr {
+	j := &ast.Ident{Name: "j"}
+	return &ast.FuncLit{
+		Type: &ast.FuncType{
+			Params: &ast.FieldList{List: []*ast.Field{&ast.Field{Names: []*ast.Ident{j}, Type: apigen.JSObject}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.ExprStmt{X: &ast.CallExpr{
+				Fun:  h,
+				Args: []ast.Expr{c.converterFor("Event")(j)},
+			}},
+		}},
+	}
+}
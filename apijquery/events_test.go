@@ -0,0 +1,17 @@
+package apijquery
+
+import "fmt"
+
+func Example_eventConstName() {
+	for _, name := range []string{"click", "dblclick", "contextmenu", "focusin", "keydown", "mouseenter", "submit"} {
+		fmt.Println(eventConstName(name))
+	}
+	//Output:
+	// EventClick
+	// EventDblClick
+	// EventContextMenu
+	// EventFocusIn
+	// EventKeyDown
+	// EventMouseEnter
+	// EventSubmit
+}
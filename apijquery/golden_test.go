@@ -0,0 +1,240 @@
+package apijquery
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ericaro/apigen"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+// a gopherjs compatible js package, configured in the options golden
+const forkImport = "github.com/example/gopherjs/js"
+
+//TestGolden parses the fixture entries in testdata/entries, compiles them, and compares
+// the generated source, and the compile report, with the golden files.
+//
+// Run "go test -update" to update the golden files, and review the diff.
+func TestGolden(t *testing.T) {
+	cases := []struct {
+		golden   string
+		report   string // golden file of the text report, "" to skip it
+		dts      string // golden file of the typescript declarations, "" to skip it
+		compiler Compiler
+		target   *apigen.Target // nil for apigen.Source
+	}{
+		{"jquery.go", "report.txt", "jquery.d.ts", Compiler{}, nil},
+		{"jquery_options.go", "", "jquery_options.d.ts", Compiler{Exported: true, Interfaces: true, Deprecated: true, Package: "jq", Import: forkImport, Global: "window.jQuery"}, nil},
+		{"jquery_gopherjs.go", "", "", Compiler{}, &apigen.GopherJS},
+		{"jquery_wasm.go", "", "", Compiler{}, &apigen.Wasm},
+		{"jquery_fake.go", "", "", Compiler{Exported: true}, &apigen.Fake}, // with the interfaces of the target
+		// the configured js import is replaced by the target one
+		{"jquery_wasm.go", "", "", Compiler{Import: "github.com/gopherjs/gopherwasm/js"}, &apigen.Wasm},
+	}
+	for _, c := range cases {
+		t.Run(c.golden, func(t *testing.T) {
+			api, err := Parse(filepath.Join("testdata", "entries"))
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			outapi, report, err := c.compiler.Compile(api)
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			outapi.Generator = "jquery-gen"
+			var src []byte
+			if c.target == nil {
+				src, err = apigen.Source(outapi)
+			} else {
+				src, err = apigen.TargetSource(outapi, *c.target)
+			}
+			if err != nil {
+				t.Fatalf("source: %v", err)
+			}
+			checkGolden(t, c.golden, src)
+			typecheck(t, src, c.target)
+			if c.dts != "" {
+				checkGolden(t, c.dts, apigen.DTS(outapi))
+			}
+
+			if c.report == "" {
+				return
+			}
+			var text bytes.Buffer
+			if err := report.WriteText(&text); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, c.report, text.Bytes())
+		})
+	}
+}
+
+//TestSourceWasmImport checks that the gopherjs code is not generated for a wasm js package
+func TestSourceWasmImport(t *testing.T) {
+	api, err := Parse(filepath.Join("testdata", "entries"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	outapi, _, err := Compiler{Import: "github.com/gopherjs/gopherwasm/js"}.Compile(api)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if _, err := apigen.Source(outapi); err == nil {
+		t.Errorf("Source accepted the gopherwasm js package")
+	}
+	if _, err := apigen.TargetSource(outapi, apigen.Wasm); err != nil {
+		t.Errorf("TargetSource(Wasm): %v", err)
+	}
+}
+
+//TestGoldenReference compares the reference documentation of the fixture entries, in both formats,
+// with the golden files in testdata/golden/reference.
+func TestGoldenReference(t *testing.T) {
+	api, err := Parse(filepath.Join("testdata", "entries"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	outapi, _, err := Compiler{Deprecated: true}.Compile(api)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	for _, format := range []string{apigen.Markdown, apigen.HTML} {
+		pages, err := apigen.Reference(outapi, format)
+		if err != nil {
+			t.Fatalf("reference: %v", err)
+		}
+		for _, page := range pages {
+			checkGolden(t, filepath.Join("reference", page.Name), page.Content)
+		}
+	}
+}
+
+//typecheck type checks the generated source 'src' of the target 't' (nil for apigen.Source).
+//
+// The gopherjs js package is not available to the tests: the gopherjs code is checked against jsfake,
+// that declares the same api, and the wasm code against syscall/js, built for GOOS=js GOARCH=wasm.
+func typecheck(t *testing.T, src []byte, target *apigen.Target) {
+	t.Helper()
+	if target != nil && target.Name == apigen.Wasm.Name {
+		t.Setenv("GOOS", "js")
+		t.Setenv("GOARCH", "wasm")
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "generated.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	std := importer.ForCompiler(fset, "gc", nil)
+	imp := importerFunc(func(path string) (*types.Package, error) {
+		switch path {
+		case apigen.GopherJS.Import, apigen.Fake.Import, forkImport:
+			return checkDir(fset, filepath.Join("..", "jsfake"), path, std)
+		}
+		return std.Import(path)
+	})
+	conf := types.Config{Importer: imp}
+	pkg, err := conf.Check(file.Name.Name, fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Errorf("the generated source does not build: %v", err)
+		return
+	}
+
+	// the methods of the embedded parents (and Object) must be promoted, not ambiguous (see apigen.Ctor)
+	for _, name := range pkg.Scope().Names() {
+		tn, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		st, ok := tn.Type().Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			if !st.Field(i).Embedded() {
+				continue
+			}
+			methods := types.NewMethodSet(st.Field(i).Type())
+			for j := 0; j < methods.Len(); j++ {
+				m := methods.At(j).Obj()
+				if obj, _, _ := types.LookupFieldOrMethod(tn.Type(), false, m.Pkg(), m.Name()); obj == nil {
+					t.Errorf("%v.%v is ambiguous", name, m.Name())
+				}
+			}
+		}
+	}
+}
+
+//checkDir type checks the go package in 'dir', without its tests
+func checkDir(fset *token.FileSet, dir, path string, imp types.Importer) (*types.Package, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: imp}
+	return conf.Check(path, fset, files, nil)
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+//checkGolden compares 'got' with the golden file testdata/golden/'name', or updates it with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", "golden", name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%v is stale (run go test -update):\n%v", golden, firstDiff(string(want), string(got)))
+	}
+}
+
+//firstDiff describes the first different line of 'want' and 'got'
+func firstDiff(want, got string) string {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(w) || i < len(g); i++ {
+		var wl, gl string
+		if i < len(w) {
+			wl = w[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if wl != gl {
+			return "line " + strconv.Itoa(i+1) + ":\n-" + wl + "\n+" + gl
+		}
+	}
+	return ""
+}
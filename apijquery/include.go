@@ -0,0 +1,292 @@
+package apijquery
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+//XInclude namespaces (the 2003 one is still found in the wild)
+var xincludeNS = map[string]bool{
+	"http://www.w3.org/2001/XInclude": true,
+	"http://www.w3.org/2003/XInclude": true,
+}
+
+//maxIncludeDepth protects against include cycles
+const maxIncludeDepth = 10
+
+var (
+	doctypeRegexp = regexp.MustCompile(`(?s)^DOCTYPE\s+\S+(?:\s+(?:SYSTEM\s+("[^"]*"|'[^']*')|PUBLIC\s+(?:"[^"]*"|'[^']*')\s+("[^"]*"|'[^']*')))?(?:\s*\[(.*)\])?`)
+	entityRegexp  = regexp.MustCompile(`<!ENTITY\s+([^\s%]+)\s+(?:("[^"]*"|'[^']*')|SYSTEM\s+("[^"]*"|'[^']*'))\s*>`)
+)
+
+//resolver expands the XIncludes, and replaces the entities of xml files
+type resolver struct {
+	// read returns the content of the file 'name' (a slash separated path), nil if includes are not supported
+	read func(name string) ([]byte, error)
+}
+
+//resolve reads the xml file 'name', and returns it with its XIncludes expanded, and its entities replaced.
+//
+// entities are the html ones, and the ones declared in the DOCTYPE (internal subset, or external dtd).
+// includes are resolved relative to the including file, either as xml (the root element, or the
+// elements selected by a simple "xpointer(/a/b)" path) or as text (parse="text").
+//
+// included files inherit the entities of the including file.
+//
+// namespaces are dropped from the result: it is meant to be unmarshalled by local names.
+func (r resolver) resolve(name string) ([]byte, error) {
+	content, err := r.readFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return r.resolveContent(name, content, xml.HTMLEntity, 0)
+}
+
+//readFile reads a file through 'read'
+func (r resolver) readFile(name string) ([]byte, error) {
+	if r.read == nil {
+		return nil, fmt.Errorf("cannot read %v: no file system", name)
+	}
+	return r.read(name)
+}
+
+//resolveContent resolves the 'content' of the file 'path'
+func (r resolver) resolveContent(path string, content []byte, inherited map[string]string, depth int) ([]byte, error) {
+	if depth > maxIncludeDepth {
+		return nil, fmt.Errorf("%v: too many nested includes", path)
+	}
+
+	entities, err := r.declaredEntities(path, content, inherited)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	d := xml.NewDecoder(bytes.NewReader(content))
+	d.Entity = entities
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", path, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "include" && (xincludeNS[t.Name.Space] || t.Name.Space == "xi") { // "xi" when the prefix is not declared
+				if err := r.include(enc, path, t, entities, depth); err != nil {
+					return nil, err
+				}
+				if err := d.Skip(); err != nil { // fallback, if any
+					return nil, fmt.Errorf("%v: %v", path, err)
+				}
+				continue
+			}
+			err = enc.EncodeToken(localStart(t))
+		case xml.EndElement:
+			err = enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: t.Name.Local}})
+		case xml.CharData:
+			err = enc.EncodeToken(t)
+		default: // comments, proc inst, and directives are not part of the content
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", path, err)
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+//include writes the resolved content of an <xi:include> element
+func (r resolver) include(enc *xml.Encoder, path string, t xml.StartElement, entities map[string]string, depth int) error {
+	var href, parse, xpointer string
+	for _, a := range t.Attr {
+		switch a.Name.Local {
+		case "href":
+			href = a.Value
+		case "parse":
+			parse = a.Value
+		case "xpointer":
+			xpointer = a.Value
+		}
+	}
+	if href == "" {
+		return fmt.Errorf("%v: xi:include without href", path)
+	}
+	target := relative(path, href)
+	content, err := r.readFile(target)
+	if err != nil {
+		return err
+	}
+	if parse == "text" {
+		return enc.EncodeToken(xml.CharData(content))
+	}
+
+	content, err = r.resolveContent(target, content, entities, depth+1)
+	if err != nil {
+		return err
+	}
+	return copySelected(enc, content, xpointerPath(xpointer))
+}
+
+//xpointerPath returns the element path of a simple "xpointer(/a/b)" expression (nil for the root element)
+func xpointerPath(xpointer string) []string {
+	xpointer = strings.TrimSpace(xpointer)
+	if !strings.HasPrefix(xpointer, "xpointer(") || !strings.HasSuffix(xpointer, ")") {
+		return nil
+	}
+	p := strings.Trim(xpointer[len("xpointer("):len(xpointer)-1], "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+//copySelected copies into 'enc' the elements of 'content' that match 'path' (or the root element if path is nil)
+func copySelected(enc *xml.Encoder, content []byte, path []string) error {
+	d := xml.NewDecoder(bytes.NewReader(content))
+	var stack []string // current element path
+	copying := 0       // depth of the copied element in the stack, 0 when not copying
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			if copying == 0 && matchPath(stack, path) {
+				copying = len(stack)
+			}
+			if copying > 0 {
+				if err := enc.EncodeToken(localStart(t)); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			if copying > 0 {
+				if err := enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: t.Name.Local}}); err != nil {
+					return err
+				}
+			}
+			if copying == len(stack) {
+				copying = 0
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if copying > 0 {
+				if err := enc.EncodeToken(t.Copy()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+//matchPath return true if the element 'stack' is selected by 'path' (nil path selects the root)
+func matchPath(stack, path []string) bool {
+	if path == nil {
+		return len(stack) == 1
+	}
+	if len(stack) != len(path) {
+		return false
+	}
+	for i := range path {
+		if path[i] != "*" && path[i] != stack[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//localStart returns a copy of 't' without namespaces
+func localStart(t xml.StartElement) xml.StartElement {
+	s := xml.StartElement{Name: xml.Name{Local: t.Name.Local}}
+	for _, a := range t.Attr {
+		if a.Name.Space == "xmlns" || a.Name.Local == "xmlns" {
+			continue
+		}
+		s.Attr = append(s.Attr, xml.Attr{Name: xml.Name{Local: a.Name.Local}, Value: a.Value})
+	}
+	return s
+}
+
+//declaredEntities returns the entities known in the file: the inherited ones, plus the ones declared in its DOCTYPE
+func (r resolver) declaredEntities(path string, content []byte, inherited map[string]string) (map[string]string, error) {
+	entities := make(map[string]string, len(inherited))
+	for k, v := range inherited {
+		entities[k] = v
+	}
+
+	// look for the DOCTYPE directive, it's before the root element
+	d := xml.NewDecoder(bytes.NewReader(content))
+	d.Strict = false
+	for {
+		tok, err := d.RawToken()
+		if err != nil {
+			return entities, nil // no doctype, the real decoding will report errors
+		}
+		if _, ok := tok.(xml.StartElement); ok {
+			return entities, nil
+		}
+		dir, ok := tok.(xml.Directive)
+		if !ok {
+			continue
+		}
+		m := doctypeRegexp.FindStringSubmatch(string(dir))
+		if m == nil {
+			continue
+		}
+		// the external dtd first, so the internal subset can override it
+		if system := unquote(m[1] + m[2]); system != "" {
+			dtd, err := r.readFile(relative(path, system))
+			if err != nil {
+				return nil, err
+			}
+			if err := r.addEntities(entities, path, string(dtd)); err != nil {
+				return nil, err
+			}
+		}
+		return entities, r.addEntities(entities, path, m[3])
+	}
+}
+
+//addEntities parses the <!ENTITY> declarations of a dtd
+func (r resolver) addEntities(entities map[string]string, path, dtd string) error {
+	for _, m := range entityRegexp.FindAllStringSubmatch(dtd, -1) {
+		if m[2] != "" {
+			entities[m[1]] = unquote(m[2])
+			continue
+		}
+		value, err := r.readFile(relative(path, unquote(m[3])))
+		if err != nil {
+			return err
+		}
+		entities[m[1]] = string(value)
+	}
+	return nil
+}
+
+//relative returns the path of 'href' relative to the file 'name'
+func relative(name, href string) string {
+	return path.Join(path.Dir(name), href)
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
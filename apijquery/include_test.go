@@ -0,0 +1,59 @@
+package apijquery
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"includes/handler.xml": `<?xml version="1.0"?>
+<signature><added>1.0</added><argument name="handler" type="Function"><desc>Called when &trig;.</desc></argument></signature>`,
+		"entries/click.xml": `<?xml version="1.0"?>
+<!DOCTYPE entry [ <!ENTITY trig "triggered"> ]>
+<entry type="method" name="click" return="jQuery" xmlns:xi="http://www.w3.org/2001/XInclude">
+  <xi:include href="../includes/handler.xml"/>
+  <xi:include href="../includes/handler.xml" xpointer="xpointer(/signature/added)"/>
+  <desc>Bind a &quot;click&quot; handler.</desc>
+</entry>`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := resolver{read: func(name string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	}}
+	content, err := r.resolve("entries/click.xml")
+	if err != nil {
+		t.Fatalf("cannot resolve: %v", err)
+	}
+	var e struct {
+		Entry
+		Added string `xml:"added"`
+	}
+	if err := xml.Unmarshal(content, &e); err != nil {
+		t.Fatalf("cannot unmarshal %s: %v", content, err)
+	}
+	if len(e.Signature) != 1 || len(e.Signature[0].Argument) != 1 {
+		t.Fatalf("included signature is missing: %s", content)
+	}
+	if got, want := e.Signature[0].Argument[0].Desc.Text(), "Called when triggered."; got != want {
+		t.Errorf("entity: got %q want %q", got, want)
+	}
+	if got, want := e.Added, "1.0"; got != want {
+		t.Errorf("xpointer include: got %q want %q", got, want)
+	}
+	if got, want := e.Desc.Text(), `Bind a "click" handler.`; got != want {
+		t.Errorf("desc: got %q want %q", got, want)
+	}
+}
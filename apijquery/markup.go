@@ -0,0 +1,252 @@
+package apijquery
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+//Site is the base url used to resolve the relative links found in the documentation
+const Site = "https://api.jquery.com"
+
+//Markup is the raw content of a documentation element (<desc>, <longdesc>)
+//
+// api.jquery.com writes them in html, Markup keeps it verbatim, so it can be
+// turned into godoc friendly text later (see Text)
+type Markup string
+
+//UnmarshalXML keeps the inner xml of the element as is
+func (m *Markup) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var raw struct {
+		Inner string `xml:",innerxml"`
+	}
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+	*m = Markup(strings.TrimSpace(raw.Inner))
+	return nil
+}
+
+//Text converts the html markup into godoc friendly text.
+//
+// paragraphs are separated by blank lines, <pre> blocks are indented with a tab,
+// list items are prefixed by "  - ", and links become godoc links, defined at the end.
+func (m Markup) Text() string {
+	if m == "" {
+		return ""
+	}
+	d := xml.NewDecoder(strings.NewReader("<markup>" + string(m) + "</markup>"))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+
+	t := new(textWriter)
+	for {
+		tok, err := d.Token()
+		if err != nil { // io.EOF or broken html: in both cases we keep what we've got so far
+			break
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			t.start(tok)
+		case xml.EndElement:
+			t.end(tok.Name.Local)
+		case xml.CharData:
+			t.text(string(tok))
+		}
+	}
+	return t.String()
+}
+
+//textWriter accumulates godoc text out of html tokens
+type textWriter struct {
+	blocks []string        // finished blocks (paragraphs, code blocks, lists)
+	line   strings.Builder // current block
+	pre    int             // depth of <pre> elements
+	list   bool            // true when the current block is a list
+	href   string          // target of the current link
+	anchor strings.Builder // text of the current link
+	links  []string        // link definitions
+	known  map[string]bool // link text already defined
+}
+
+func (t *textWriter) start(e xml.StartElement) {
+	switch e.Name.Local {
+	case "p", "div", "blockquote", "table", "tr":
+		t.block()
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		t.block()
+		t.line.WriteString("# ")
+	case "ul", "ol":
+		t.block()
+		t.list = true
+	case "li":
+		if t.line.Len() > 0 {
+			t.line.WriteString("\n")
+		}
+		t.line.WriteString("  - ")
+	case "pre":
+		t.block()
+		t.pre++
+	case "br":
+		if t.pre == 0 {
+			t.block()
+		}
+	case "a":
+		for _, a := range e.Attr {
+			if a.Name.Local == "href" {
+				t.href = a.Value
+			}
+		}
+		t.anchor.Reset()
+	}
+}
+
+func (t *textWriter) end(name string) {
+	switch name {
+	case "p", "div", "blockquote", "table", "tr", "h1", "h2", "h3", "h4", "h5", "h6":
+		t.block()
+	case "ul", "ol":
+		t.block()
+		t.list = false
+	case "pre":
+		t.pre--
+		if t.pre == 0 {
+			t.code()
+		}
+	case "a":
+		t.link()
+	}
+}
+
+func (t *textWriter) text(s string) {
+	if t.pre > 0 {
+		t.line.WriteString(s)
+		return
+	}
+	// collapse white spaces
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		if s != "" && t.line.Len() > 0 {
+			t.space()
+		}
+		return
+	}
+	if s[0] == ' ' || s[0] == '\t' || s[0] == '\n' || s[0] == '\r' {
+		t.space()
+	}
+	collapsed := strings.Join(words, " ")
+	t.line.WriteString(collapsed)
+	if t.href != "" {
+		t.anchor.WriteString(collapsed)
+	}
+	if last := s[len(s)-1]; last == ' ' || last == '\t' || last == '\n' || last == '\r' {
+		t.space()
+	}
+}
+
+//space appends a single space to the current line, if it needs one.
+func (t *textWriter) space() {
+	s := t.line.String()
+	if s != "" && !strings.HasSuffix(s, " ") && !strings.HasSuffix(s, "\n") {
+		t.line.WriteString(" ")
+		if t.href != "" {
+			t.anchor.WriteString(" ")
+		}
+	}
+}
+
+//link turns the last anchor text into a godoc link "[text]" and records its definition.
+func (t *textWriter) link() {
+	href, text := t.href, strings.TrimSpace(t.anchor.String())
+	t.href = ""
+	if t.pre > 0 || text == "" || strings.ContainsAny(text, "[]") {
+		return
+	}
+	switch {
+	case strings.HasPrefix(href, "#"), href == "":
+		return // local anchors are meaningless in godoc
+	case strings.HasPrefix(href, "//"):
+		href = "https:" + href
+	case strings.HasPrefix(href, "/"):
+		href = Site + href
+	}
+	s := t.line.String()
+	i := strings.LastIndex(s, text)
+	if i < 0 {
+		return
+	}
+	t.line.Reset()
+	t.line.WriteString(s[:i] + "[" + text + "]" + s[i+len(text):])
+
+	if t.known == nil {
+		t.known = make(map[string]bool)
+	}
+	if !t.known[text] {
+		t.known[text] = true
+		t.links = append(t.links, "["+text+"]: "+href)
+	}
+}
+
+//block ends the current block of text.
+func (t *textWriter) block() {
+	if t.pre > 0 {
+		return
+	}
+	s := strings.TrimSpace(t.line.String())
+	if t.list {
+		s = strings.TrimRight(t.line.String(), " \n")
+	}
+	t.line.Reset()
+	if s != "" {
+		t.blocks = append(t.blocks, s)
+	}
+}
+
+//code ends the current <pre> block, turning it into an indented block.
+func (t *textWriter) code() {
+	lines := strings.Split(strings.Trim(t.line.String(), "\r\n"), "\n")
+	t.line.Reset()
+	indent := commonIndent(lines)
+	for i, l := range lines {
+		l = strings.TrimRight(l, " \t\r")
+		if len(l) >= len(indent) {
+			l = l[len(indent):]
+		}
+		if l != "" {
+			l = "\t" + l
+		}
+		lines[i] = l
+	}
+	if s := strings.Join(lines, "\n"); strings.TrimSpace(s) != "" {
+		t.blocks = append(t.blocks, s)
+	}
+}
+
+//commonIndent returns the white space prefix shared by all non blank lines.
+func commonIndent(lines []string) string {
+	indent, first := "", true
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		ws := l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+		switch {
+		case first:
+			indent, first = ws, false
+		default:
+			for !strings.HasPrefix(ws, indent) {
+				indent = indent[:len(indent)-1]
+			}
+		}
+	}
+	return indent
+}
+
+func (t *textWriter) String() string {
+	t.block()
+	blocks := t.blocks
+	if len(t.links) > 0 {
+		blocks = append(blocks, strings.Join(t.links, "\n"))
+	}
+	return strings.Join(blocks, "\n\n")
+}
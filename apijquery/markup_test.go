@@ -0,0 +1,20 @@
+package apijquery
+
+import "fmt"
+
+func ExampleMarkup_Text() {
+	m := Markup(`<p>Use <a href="/jQuery.ajax/">jQuery.ajax()</a> to   load
+	data.</p><pre><code>
+    $.ajax( "test.html" );
+</code></pre><ul><li>one</li><li>two</li></ul>`)
+	fmt.Println(m.Text())
+	//Output:
+	// Use [jQuery.ajax()] to load data.
+	//
+	//	$.ajax( "test.html" );
+	//
+	//   - one
+	//   - two
+	//
+	// [jQuery.ajax()]: https://api.jquery.com/jQuery.ajax/
+}
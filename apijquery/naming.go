@@ -0,0 +1,113 @@
+package apijquery
+
+import (
+	"strings"
+	"unicode"
+)
+
+//Initialisms are words that must keep a consistent case in go identifiers (golint rules)
+//
+// when found in a javascript name, they are written all upper case (or all lower case at
+// the start of an unexported name)
+var Initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "LHS": true, "QPS": true, "RAM": true, "RHS": true,
+	"RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true, "XMPP": true,
+	"XSRF": true, "XSS": true,
+}
+
+//Overrides maps javascript names to the go name to be used, when the rules don't do the job.
+var Overrides = map[string]string{
+	"jQuery": "JQuery",
+	"jqXHR":  "JqXHR",
+
+	// the compound event names, for the shorthand methods and the event constants
+	"contextmenu": "ContextMenu",
+	"dblclick":    "DblClick",
+	"focusin":     "FocusIn",
+	"focusout":    "FocusOut",
+	"keydown":     "KeyDown",
+	"keypress":    "KeyPress",
+	"keyup":       "KeyUp",
+	"mousedown":   "MouseDown",
+	"mouseenter":  "MouseEnter",
+	"mouseleave":  "MouseLeave",
+	"mousemove":   "MouseMove",
+	"mouseout":    "MouseOut",
+	"mouseover":   "MouseOver",
+	"mouseup":     "MouseUp",
+}
+
+//GoName returns the exported go identifier for a javascript name.
+//
+// the name is split into camel case words, each word is title cased, or upper cased if it
+// is an initialism: "parseJSON" -> "ParseJSON", "html" -> "HTML", "ajaxSetup" -> "AjaxSetup".
+//
+// Overrides take precedence over the rules.
+func GoName(name string) string {
+	if o, exists := Overrides[name]; exists {
+		return o
+	}
+	words := splitWords(name)
+	for i, w := range words {
+		words[i] = exportWord(w)
+	}
+	return strings.Join(words, "")
+}
+
+//GoLocalName returns the unexported go identifier for a javascript name (for parameters).
+//
+// it follows the same rules as GoName, except that the first word is lower cased:
+// "url" -> "url", "elementId" -> "elementID", "URLString" -> "urlString".
+func GoLocalName(name string) string {
+	words := splitWords(name)
+	for i, w := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(w)
+		} else {
+			words[i] = exportWord(w)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+//exportWord title cases a single word, or upper cases it if it's an initialism
+func exportWord(w string) string {
+	if u := strings.ToUpper(w); Initialisms[u] {
+		return u
+	}
+	return Title(strings.ToLower(w))
+}
+
+//splitWords splits a camel case name into words.
+//
+// an upper case run is a word on its own ("parseXMLDoc" -> parse XML Doc), digits stick
+// to the previous word, and every other non alphanumeric char is a separator.
+func splitWords(name string) (words []string) {
+	runes := []rune(name)
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			words = append(words, string(runes[start:end]))
+		}
+		start = end
+	}
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush(i)
+			start = i + 1
+		case i == start || !unicode.IsUpper(r):
+			// nothing to do
+		case unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]):
+			flush(i) // "parseJSON": a new word starts at J
+		case i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			flush(i) // "XMLDoc": a new word starts at D
+		}
+	}
+	flush(len(runes))
+	return
+}
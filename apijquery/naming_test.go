@@ -0,0 +1,30 @@
+package apijquery
+
+import "fmt"
+
+func ExampleGoName() {
+	for _, name := range []string{"html", "css", "ajaxSetup", "parseJSON", "isXMLDoc", "innerHTML", "jQuery", "uniqueId", "dblclick"} {
+		fmt.Println(GoName(name))
+	}
+	//Output:
+	// HTML
+	// CSS
+	// AjaxSetup
+	// ParseJSON
+	// IsXMLDoc
+	// InnerHTML
+	// JQuery
+	// UniqueID
+	// DblClick
+}
+
+func ExampleGoLocalName() {
+	for _, name := range []string{"url", "htmlString", "elementId", "URLString"} {
+		fmt.Println(GoLocalName(name))
+	}
+	//Output:
+	// url
+	// htmlString
+	// elementID
+	// urlString
+}
@@ -1,73 +1,280 @@
 package apijquery
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
 	"encoding/xml"
-	"io/ioutil"
+	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 )
 
 //Parse the api.jquery.com directory for all *.xml files
 //
+// includes can reach files outside of 'directory' (like "../includes/foo.xml")
+//
 // return the current api.Api instance
 func Parse(directory string) (api *Api, err error) {
+	read := func(name string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(directory, filepath.FromSlash(name)))
+	}
+	return parseFS(os.DirFS(directory), resolver{read: read})
+}
 
-	p := &parser{api: NewApi()}
-	err = filepath.Walk(directory, p.walk)
-	return p.api, err
+//ParseFS parses all *.xml files of 'fsys', like an embed.FS
+//
+// includes are resolved within 'fsys'
+func ParseFS(fsys fs.FS) (api *Api, err error) {
+	read := func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	}
+	return parseFS(fsys, resolver{read: read})
 }
 
-type parser struct {
-	api *Api
+//ParseReader parses a single xml document (an <entry> or an <entries>)
+//
+// entities are replaced, but XIncludes are not supported: there is no file system to read them from
+func ParseReader(r io.Reader) (api *Api, err error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	content, err = resolver{}.resolveContent("<reader>", content, xml.HTMLEntity, 0)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decode(content)
+	api = NewApi()
+	api.add(doc)
+	return api, err
 }
 
-func (p *parser) walk(path string, info os.FileInfo, err error) error {
+//ParseArchive parses all *.xml files of a .zip, .tar.gz or .tgz archive
+func ParseArchive(name string) (api *Api, err error) {
+	fsys, err := OpenArchive(name)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if c, ok := fsys.(io.Closer); ok {
+		defer c.Close()
 	}
+	return ParseFS(fsys)
+}
 
-	//path is the actual file
-	if strings.HasSuffix(path, ".xml") {
+//IsArchive returns true if 'name' has the extension of an archive supported by OpenArchive
+func IsArchive(name string) bool {
+	for _, ext := range []string{".zip", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
 
-		file, err := os.Open(path)
+//OpenArchive opens a .zip, .tar.gz or .tgz archive as a file system
+//
+// the returned fs.FS might implement io.Closer, it must be closed then.
+func OpenArchive(name string) (fs.FS, error) {
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		z, err := zip.OpenReader(name)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		return z, nil
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
 		}
-		defer file.Close()
+		defer f.Close()
+		return readTarGz(f)
+	default:
+		return nil, fmt.Errorf("%v: unsupported archive, expecting .zip, .tar.gz or .tgz", name)
+	}
+}
 
-		//read content then try to parse
-		content, err := ioutil.ReadAll(file)
+//readTarGz reads a gzipped tar archive in memory.
+//
+// the files are copied into an uncompressed zip archive, that can be read as a file system.
+func readTarGz(r io.Reader) (fs.FS, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			return err
+			return nil, err
 		}
+		if h.Typeflag != tar.TypeReg {
+			continue // directories are implied by the files
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     path.Clean(strings.TrimPrefix(h.Name, "/")),
+			Method:   zip.Store,
+			Modified: h.ModTime,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(w, tr); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return nil, err
+	}
+	return z, nil
+}
 
-		//the content can be either an <entry> or and <entries>
-		//
-		//Attempt parsing "both" ways and keep only if not empty
-		//log.Printf("Parsing %v", path)
+//Workers is the number of files parsed concurrently
+var Workers = runtime.GOMAXPROCS(0)
 
-		//try as an Entry
-		e := new(Entry)
-		err = xml.Unmarshal(content, e)
+//parseFS parses every *.xml file of 'fsys', read through 'r', with a pool of Workers.
+//
+// files are added to the api sorted by path, so the result does not depend on the scheduling.
+func parseFS(fsys fs.FS, r resolver) (*Api, error) {
+	var files []string
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if !d.IsDir() && strings.HasSuffix(path, ".xml") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return NewApi(), err
+	}
+	sort.Strings(files)
 
-		if e.RawName != "" { //this was an entry (it's not empty)
-			p.api.Entry = append(p.api.Entry, e)
-		} else { //this was not an "<entry>" (it's empty)
-			//try as an "<entries>"
-			entries := new(Entries)
-			err = xml.Unmarshal(content, entries)
-			if err != nil {
-				return err
+	docs := make([]document, len(files))
+	errs := make([]error, len(files))
+	jobs := make(chan int)
+	workers := Workers
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				docs[i], errs[i] = r.parseFile(files[i])
 			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
-			if len(entries.Entry) != 0 { //there was entries, add them
-				p.api.Entries = append(p.api.Entries, entries)
-			}
+	api := NewApi()
+	for i, doc := range docs {
+		if errs[i] != nil {
+			return api, fmt.Errorf("%v: %v", files[i], errs[i])
 		}
+		api.add(doc)
+	}
+	return api, nil
+}
+
+//document is a parsed xml file: either an <entry> or an <entries>, both nil for an ignored file
+type document struct {
+	entry   *Entry
+	entries *Entries
+}
+
+//parseFile reads, resolves and decodes the file 'name'
+func (r resolver) parseFile(name string) (document, error) {
+	content, err := r.readFile(name)
+	if err != nil {
+		return document{}, err
+	}
+	//skip fragments meant to be included
+	if root := rootElement(content); root != "entry" && root != "entries" {
+		return document{}, nil
+	}
+	content, err = r.resolveContent(name, content, xml.HTMLEntity, 0)
+	if err != nil {
+		return document{}, err
+	}
+	return decode(content)
+}
+
+//rootElement returns the local name of the root element of an xml document ("" if there is none)
+func rootElement(content []byte) string {
+	d := xml.NewDecoder(bytes.NewReader(content))
+	d.Strict = false
+	for {
+		tok, err := d.RawToken()
+		if err != nil {
+			return ""
+		}
+		if t, ok := tok.(xml.StartElement); ok {
+			return t.Name.Local
+		}
+	}
+}
+
+//decode unmarshals a resolved xml document, the root element tells whether it's an <entry> or an <entries>
+func decode(content []byte) (doc document, err error) {
+	d := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return doc, nil // no root element
+		}
+		if err != nil {
+			return doc, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "entry":
+			doc.entry = new(Entry)
+			err = d.DecodeElement(doc.entry, &start)
+		case "entries":
+			doc.entries = new(Entries)
+			err = d.DecodeElement(doc.entries, &start)
+		}
+		return doc, err
+	}
+}
+
+//add adds a parsed document to the api, empty ones are dropped
+func (api *Api) add(doc document) {
+	if doc.entry != nil && doc.entry.RawName != "" {
+		api.Entry = append(api.Entry, doc.entry)
+	}
+	if doc.entries != nil && len(doc.entries.Entry) != 0 {
+		api.Entries = append(api.Entries, doc.entries)
 	}
-	return nil
 }
@@ -0,0 +1,152 @@
+package apijquery
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var parserFiles = map[string]string{
+	"includes/handler.xml": `<signature><argument name="handler" type="Function"><desc>Called on &trig;.</desc></argument></signature>`,
+	"entries/click.xml": `<!DOCTYPE entry [ <!ENTITY trig "click"> ]>
+<entry type="method" name="click" return="jQuery" xmlns:xi="http://www.w3.org/2001/XInclude">
+  <xi:include href="../includes/handler.xml"/>
+  <desc>Bind a handler.</desc>
+</entry>`,
+	"entries/css.xml": `<entries>
+  <entry type="method" name="css" return="String"><signature><argument name="propertyName" type="String"/></signature></entry>
+  <entry type="method" name="css" return="jQuery"><signature><argument name="propertyName" type="String"/><argument name="value" type="String"/></signature></entry>
+</entries>`,
+	"README.md": "not parsed",
+}
+
+//checkParsed checks the api parsed from parserFiles
+func checkParsed(t *testing.T, api *Api) {
+	t.Helper()
+	if len(api.Entry) != 1 || len(api.Entries) != 1 {
+		t.Fatalf("got %d entry and %d entries, want 1 and 1", len(api.Entry), len(api.Entries))
+	}
+	click := api.Entry[0]
+	if len(click.Signature) != 1 || len(click.Signature[0].Argument) != 1 {
+		t.Fatalf("included signature is missing")
+	}
+	if got, want := click.Signature[0].Argument[0].Desc.Text(), "Called on click."; got != want {
+		t.Errorf("entity: got %q want %q", got, want)
+	}
+	if got := len(api.Entries[0].Entry); got != 2 {
+		t.Errorf("got %d entries in the group, want 2", got)
+	}
+}
+
+func TestParseFS(t *testing.T) {
+	fsys := make(fstest.MapFS)
+	for name, content := range parserFiles {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	api, err := ParseFS(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkParsed(t, api)
+}
+
+func TestParseFS_zip(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range parserFiles {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, err := ParseFS(z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkParsed(t, api)
+}
+
+func TestReadTarGz(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	for name, content := range parserFiles {
+		if err := w.WriteHeader(&tar.Header{Name: "api/" + name, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	fsys, err := readTarGz(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, err := ParseFS(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkParsed(t, api)
+}
+
+func TestParseReader(t *testing.T) {
+	api, err := ParseReader(strings.NewReader(parserFiles["entries/css.xml"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(api.Entries) != 1 || len(api.Entries[0].Entry) != 2 {
+		t.Fatalf("got %v entries", len(api.Entries))
+	}
+
+	// includes need a file system
+	if _, err := ParseReader(strings.NewReader(parserFiles["entries/click.xml"])); err == nil {
+		t.Errorf("expecting an error for an include without a file system")
+	}
+}
+
+func TestParseFS_order(t *testing.T) {
+	defer func(w int) { Workers = w }(Workers)
+	Workers = 8
+
+	fsys := make(fstest.MapFS)
+	var want []string
+	for i := 0; i < 100; i++ {
+		name := fmt.Sprintf("e%03d", i)
+		fsys["entries/"+name+".xml"] = &fstest.MapFile{Data: []byte(`<entry type="method" name="` + name + `" return=""/>`)}
+		want = append(want, name)
+	}
+	for run := 0; run < 3; run++ {
+		api, err := ParseFS(fsys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, e := range api.Entry {
+			got = append(got, e.RawName)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("run %d: entries are not sorted by path: %v", run, got)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package apijquery
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+//Report explains the decisions made by the compiler, to track the quality of the binding
+type Report struct {
+	Skipped   []Skipped        `json:"skipped"`   // entries not generated
+	Accessors []Classification `json:"accessors"` // groups of entries, split into getter/setter families or not
+	Merged    []Merged         `json:"merged"`    // entries merged into a single method
+	Widened   []Widened        `json:"widened"`   // signatures of the same arity merged into one
+	Variadic  []Variadic       `json:"variadic"`  // signatures collapsed to ...interface{}
+	Renamed   []Renamed        `json:"renamed"`   // entries whose go name is not derived from their js name
+	Fallbacks []Fallback       `json:"fallbacks"` // jquery types generated as *js.Object
+}
+
+//Skipped is an entry that has not been generated
+type Skipped struct {
+	Name   string `json:"name"`   // raw name of the entry
+	Reason string `json:"reason"` // like "deprecated in 1.8"
+}
+
+//Merged is an entry merged with another entry of the same name
+type Merged struct {
+	Name    string   `json:"name"`    // raw name of the entries
+	GoName  string   `json:"goName"`  // name of the generated method
+	Returns []string `json:"returns"` // return types of both entries
+	Return  string   `json:"return"`  // return type of the merged entry
+}
+
+//Widened is a method whose signatures of the same arity have been merged, the differing arguments widened to interface{}
+type Widened struct {
+	Name       string   `json:"name"`       // raw name of the entry
+	GoName     string   `json:"goName"`     // go name of the method
+	Signatures []string `json:"signatures"` // the original signatures, like "(name, value)"
+	Arguments  []string `json:"arguments"`  // names of the widened arguments, none if the signatures are the same
+}
+
+//Variadic is a method whose signatures have been collapsed to a single ...interface{} argument
+type Variadic struct {
+	Name       string   `json:"name"`       // raw name of the entry
+	GoName     string   `json:"goName"`     // go name of the method
+	Signatures []string `json:"signatures"` // the original signatures, like "(name, value)"
+	Reason     string   `json:"reason"`     // "several signatures" or "optional arguments"
+}
+
+//Renamed is an entry generated with a go name not derived from its js name
+type Renamed struct {
+	Name   string `json:"name"`   // raw name of the entry
+	GoName string `json:"goName"` // go name of the generated method
+	Reason string `json:"reason"`
+}
+
+//Fallback is a jquery type generated as *js.Object, because it has no go equivalent
+type Fallback struct {
+	Name  string `json:"name"`  // raw name of the entry
+	Where string `json:"where"` // "return", "property", or the argument name
+	Type  string `json:"type"`  // the jquery type
+}
+
+//skip records an entry that is not generated
+func (r *Report) skip(e *Entry) {
+	reason := "deprecated in " + e.Deprecated
+	switch {
+	case e.Removed != "" && e.Deprecated != "":
+		reason = "removed in " + e.Removed + ", " + reason
+	case e.Removed != "":
+		reason = "removed in " + e.Removed
+	}
+	r.Skipped = append(r.Skipped, Skipped{Name: e.RawName, Reason: reason})
+}
+
+//rename records an entry (formerly named 'name') whose go name has changed
+func (r *Report) rename(name string, e *Entry, reason string) {
+	r.Renamed = append(r.Renamed, Renamed{Name: name, GoName: e.GoName(), Reason: reason})
+}
+
+//fallbacks records the types of a compiled entry that fall back to *js.Object
+func (r *Report) fallbacks(e *Entry) {
+	where := "return"
+	if e.Type == "property" {
+		where = "property"
+	}
+	if isFallback(e.Return) {
+		r.Fallbacks = append(r.Fallbacks, Fallback{Name: e.RawName, Where: where, Type: e.Return})
+	}
+	if len(e.Signature) == 0 {
+		return
+	}
+	for _, a := range e.Signature[0].Argument {
+		if isFallback(a.Type) {
+			r.Fallbacks = append(r.Fallbacks, Fallback{Name: e.RawName, Where: a.Name, Type: a.Type})
+		}
+	}
+}
+
+//WriteText writes the report in a human readable form, one section per kind of decision
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	section := func(title string, n int) {
+		fmt.Fprintf(tw, "%v (%d):\n", title, n)
+	}
+	section("skipped", len(r.Skipped))
+	for _, s := range r.Skipped {
+		fmt.Fprintf(tw, "\t%v\t%v\n", s.Name, s.Reason)
+	}
+	section("groups", len(r.Accessors))
+	for _, c := range r.Accessors {
+		fmt.Fprintf(tw, "\t%v\n", c)
+	}
+	section("merged", len(r.Merged))
+	for _, m := range r.Merged {
+		fmt.Fprintf(tw, "\t%v\t%v\t%v -> %v\n", m.Name, m.GoName, strings.Join(m.Returns, " <> "), m.Return)
+	}
+	section("widened", len(r.Widened))
+	for _, w := range r.Widened {
+		fmt.Fprintf(tw, "\t%v\t%v\t%v\t%v\n", w.Name, w.GoName, strings.Join(w.Arguments, ", "), strings.Join(w.Signatures, " "))
+	}
+	section("variadic", len(r.Variadic))
+	for _, v := range r.Variadic {
+		fmt.Fprintf(tw, "\t%v\t%v\t%v\t%v\n", v.Name, v.GoName, v.Reason, strings.Join(v.Signatures, " "))
+	}
+	section("renamed", len(r.Renamed))
+	for _, n := range r.Renamed {
+		fmt.Fprintf(tw, "\t%v\t%v\t%v\n", n.Name, n.GoName, n.Reason)
+	}
+	section("*js.Object", len(r.Fallbacks))
+	for _, f := range r.Fallbacks {
+		fmt.Fprintf(tw, "\t%v\t%v\t%v\n", f.Name, f.Where, f.Type)
+	}
+	return tw.Flush()
+}
+
+//Summary returns a single line summary of the report
+func (r *Report) Summary() string {
+	families := 0
+	for _, c := range r.Accessors {
+		if c.Family {
+			families++
+		}
+	}
+	return fmt.Sprintf("%d skipped, %d getter/setter, %d merged, %d widened, %d variadic, %d renamed, %d *js.Object",
+		len(r.Skipped), families, len(r.Merged), len(r.Widened), len(r.Variadic), len(r.Renamed), len(r.Fallbacks))
+}
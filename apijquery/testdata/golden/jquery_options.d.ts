@@ -0,0 +1,242 @@
+// Code generated by jquery-gen. DO NOT EDIT.
+
+declare namespace jq {
+	/**
+	 * JQuery wraps a jQuery object: a set of matched DOM elements.
+	 *
+	 * See https://api.jquery.com/Types/#jQuery
+	 */
+	interface JQuery {
+		/**
+		 * Adds the specified class(es) to each element in the set of matched elements.
+		 *
+		 * It's important to note that this method does not replace a class. It simply
+		 * adds the class, appending it to any which may already be assigned to the
+		 * elements. See [.removeClass()].
+		 *
+		 *	$( "p" ).addClass( "myClass yourClass" );
+		 *
+		 * [.removeClass()]: https://api.jquery.com/removeClass/
+		 */
+		addClass(className: string): JQuery;
+		/**
+		 * Get the value of an attribute for the first element in the set of matched
+		 * elements.
+		 */
+		attr(attributeName: string): string;
+		/**
+		 * Get the computed style properties for the first element in the set of matched
+		 * elements.
+		 */
+		css(propertyName: string): string;
+		/**
+		 * Bind an event handler to the "click" JavaScript event, & trigger it.
+		 */
+		click(handler: (arg0: Event) => void): JQuery;
+		/**
+		 * Bind an event handler to the "dblclick" JavaScript event, or trigger that
+		 * event on an element.
+		 *
+		 * @deprecated since jQuery 3.3
+		 */
+		dblclick(handler: (arg0: Event) => void): JQuery;
+		/**
+		 * Set a timer to delay execution of subsequent items in the queue.
+		 */
+		delay(duration: any): JQuery;
+		/**
+		 * Display the matched elements by fading them to opaque.
+		 */
+		fadeIn(...i: any[]): JQuery;
+		/**
+		 * Get the HTML contents of the first element in the set of matched elements.
+		 */
+		html(): string;
+		/**
+		 * Remove an event handler.
+		 *
+		 * It removes all the handlers of the events, the go handlers cannot be told
+		 * apart: name the events with a namespace, like "click.myPlugin", to remove
+		 * only some of them.
+		 */
+		off(events: string): JQuery;
+		/**
+		 * Remove an event handler.
+		 *
+		 * It removes the handlers delegated with the selector.
+		 *
+		 * It removes all the handlers of the events, the go handlers cannot be told
+		 * apart: name the events with a namespace, like "click.myPlugin", to remove
+		 * only some of them.
+		 */
+		off(events: string, selector: string): JQuery;
+		/**
+		 * Attach an event handler function for one or more events to the selected
+		 * elements.
+		 */
+		on(events: string, handler: (arg0: Event) => void): JQuery;
+		/**
+		 * Attach an event handler function for one or more events to the selected
+		 * elements.
+		 *
+		 * The handler is called only for the descendants of the selected elements that
+		 * match the selector.
+		 */
+		on(events: string, selector: string, handler: (arg0: Event) => void): JQuery;
+		/**
+		 * Get the value of a property for the first element in the set of matched
+		 * elements.
+		 */
+		prop(propertyName: string): BooleanOrNumberOrString;
+		/**
+		 * Get the current vertical position of the scroll bar.
+		 */
+		scrollTop(): number;
+		/**
+		 * Get or set attributes.
+		 */
+		attr(attributeName: string, value: any): JQuery;
+		/**
+		 * Set one or more attributes for the set of matched elements.
+		 */
+		attr(attributes: { [key: string]: any }): JQuery;
+		/**
+		 * Set one or more CSS properties for the set of matched elements.
+		 */
+		css(propertyName: string, value: string): JQuery;
+		/**
+		 * Set one or more CSS properties for the set of matched elements.
+		 */
+		css(properties: { [key: string]: any }): JQuery;
+		/**
+		 * Set the HTML contents of each element in the set of matched elements.
+		 */
+		html(htmlString: string): JQuery;
+		/**
+		 * Add or remove one or more classes from each element in the set of matched
+		 * elements, depending on either the class's presence or the value of the state
+		 * argument.
+		 */
+		toggleClass(...i: any[]): JQuery;
+		/**
+		 * Execute all handlers and behaviors attached to the matched elements for the
+		 * given event type.
+		 *
+		 * The extra parameters are passed along to the handlers, after the event.
+		 */
+		trigger(eventType: string, extraParameters: any[]): JQuery;
+		/**
+		 * Get the current value of the first element in the set of matched elements.
+		 */
+		val(): any;
+	}
+
+	/**
+	 * Element wraps a DOM element, like the target of an event.
+	 *
+	 * See https://api.jquery.com/Types/#Element
+	 */
+	interface Element {
+	}
+
+	/**
+	 * Callbacks wraps a multi-purpose callbacks list object, as returned by
+	 * jQuery.Callbacks().
+	 *
+	 * See https://api.jquery.com/jQuery.Callbacks/
+	 */
+	interface Callbacks {
+	}
+
+	/**
+	 * Deferred wraps a chainable utility object, as returned by jQuery.Deferred().
+	 *
+	 * See https://api.jquery.com/category/deferred-object/
+	 */
+	interface Deferred {
+		/**
+		 * Add handlers to be called when the Deferred object is resolved.
+		 */
+		done(doneCallbacks: any): Deferred;
+	}
+
+	/**
+	 * Event wraps a jQuery event object, normalized according to W3C standards.
+	 *
+	 * See https://api.jquery.com/category/events/event-object/
+	 */
+	interface Event {
+		/**
+		 * The mouse position relative to the left edge of the document.
+		 */
+		pageX: number;
+		/**
+		 * The DOM element that initiated the event.
+		 */
+		target: Element;
+		/**
+		 * For key or mouse events, this property indicates the specific key or button
+		 * that was pressed.
+		 */
+		which: number;
+	}
+
+	/**
+	 * JqXHR wraps the jQuery XMLHttpRequest object, as returned by jQuery.ajax().
+	 * It is a Deferred.
+	 *
+	 * See https://api.jquery.com/jQuery.ajax/#jqXHR
+	 */
+	interface JqXHR extends Deferred {
+	}
+
+	/**
+	 * BooleanOrNumberOrString is either a Boolean, or a Number, or a String.
+	 *
+	 * Use a type switch to get the actual value.
+	 */
+	type BooleanOrNumberOrString = boolean | number | string;
+
+	/**
+	 * JQ returns the jQuery global object, the receiver of the static jQuery
+	 * functions.
+	 *
+	 * It is looked up at each call, so jQuery can be loaded after the package init.
+	 */
+	interface JQStatic {
+		/**
+		 * NewCallbacks creates a new Callbacks, with window.jQuery.Callbacks().
+		 *
+		 * A multi-purpose callbacks list object that provides a powerful way to manage
+		 * callback lists.
+		 */
+		Callbacks: new (flags: string) => Callbacks;
+		/**
+		 * NewDeferred creates a new Deferred, with window.jQuery.Deferred().
+		 *
+		 * A factory function that returns a chainable utility object with methods to
+		 * register multiple callbacks into callback queues, invoke callback queues, and
+		 * relay the success or failure state of any synchronous or asynchronous
+		 * function.
+		 */
+		Deferred: new (...i: any[]) => Deferred;
+		/**
+		 * NewEvent creates a new jQuery event object, to be triggered.
+		 *
+		 * See https://api.jquery.com/category/events/event-object/
+		 */
+		Event: new (src: string) => Event;
+		/**
+		 * Perform an asynchronous HTTP (Ajax) request.
+		 */
+		ajax(...i: any[]): JqXHR;
+		/**
+		 * Remove the whitespace from the beginning and end of a string.
+		 *
+		 * @deprecated since jQuery 3.5
+		 */
+		trim(str: string): string;
+	}
+}
+
+declare const jQuery: jq.JQStatic;
@@ -0,0 +1,430 @@
+// Code generated by jquery-gen. DO NOT EDIT.
+
+package jq
+
+import (
+	"github.com/example/gopherjs/js"
+)
+
+const (
+	// EventClick is the name of the "click" event.
+	EventClick = "click"
+	// EventDblClick is the name of the "dblclick" event.
+	EventDblClick = "dblclick"
+)
+
+// JQ returns the jQuery global object, the receiver of the static jQuery
+// functions.
+//
+// It is looked up at each call, so jQuery can be loaded after the package init.
+func JQ() *js.Object {
+	return js.Global.Get("window").Get("jQuery")
+}
+
+// JQuery wraps a jQuery object: a set of matched DOM elements.
+//
+// See https://api.jquery.com/Types/#jQuery
+type JQuery struct {
+	*js.Object
+}
+
+// WrapJQuery returns the JQuery wrapping an existing javascript object.
+func WrapJQuery(j *js.Object) JQuery {
+	return JQuery{Object: j}
+}
+
+// JQueryAPI is the interface of the methods of JQuery.
+type JQueryAPI interface {
+	AddClass(className string) JQuery
+	Attr(attributeName string) string
+	CSS(propertyName string) string
+	Click(handler func(Event)) JQuery
+	DblClick(handler func(Event)) JQuery
+	Delay(duration interface{}) JQuery
+	FadeIn(i ...interface{}) JQuery
+	HTML() string
+	Off(events string) JQuery
+	OffDelegated(events string, selector string) JQuery
+	On(events string, handler func(Event)) JQuery
+	OnDelegated(events string, selector string, handler func(Event)) JQuery
+	Prop(propertyName string) BooleanOrNumberOrString
+	ScrollTop() float64
+	SetAttr(attributeName string, value interface{}) JQuery
+	SetAttrMap(attributes map[string]interface{}) JQuery
+	SetCSS(propertyName string, value string) JQuery
+	SetCSSMap(properties map[string]interface{}) JQuery
+	SetHTML(htmlString string) JQuery
+	ToggleClass(i ...interface{}) JQuery
+	Trigger(eventType string, extraParameters ...interface{}) JQuery
+	Val() *js.Object
+}
+
+var _ JQueryAPI = JQuery{}
+
+// Element wraps a DOM element, like the target of an event.
+//
+// See https://api.jquery.com/Types/#Element
+type Element struct {
+	*js.Object
+}
+
+// WrapElement returns the Element wrapping an existing javascript object.
+func WrapElement(j *js.Object) Element {
+	return Element{Object: j}
+}
+
+// ElementAPI is the interface of the methods of Element.
+type ElementAPI interface{}
+
+var _ ElementAPI = Element{}
+
+// Callbacks wraps a multi-purpose callbacks list object, as returned by
+// jQuery.Callbacks().
+//
+// See https://api.jquery.com/jQuery.Callbacks/
+type Callbacks struct {
+	*js.Object
+}
+
+// WrapCallbacks returns the Callbacks wrapping an existing javascript object.
+func WrapCallbacks(j *js.Object) Callbacks {
+	return Callbacks{Object: j}
+}
+
+// NewCallbacks creates a new Callbacks, with window.jQuery.Callbacks().
+//
+// A multi-purpose callbacks list object that provides a powerful way to manage
+// callback lists.
+func NewCallbacks(flags string) Callbacks {
+	return WrapCallbacks(js.Global.Get("window").Get("jQuery").Get("Callbacks").New(flags))
+}
+
+// CallbacksAPI is the interface of the methods of Callbacks.
+type CallbacksAPI interface{}
+
+var _ CallbacksAPI = Callbacks{}
+
+// Deferred wraps a chainable utility object, as returned by jQuery.Deferred().
+//
+// See https://api.jquery.com/category/deferred-object/
+type Deferred struct {
+	*js.Object
+}
+
+// WrapDeferred returns the Deferred wrapping an existing javascript object.
+func WrapDeferred(j *js.Object) Deferred {
+	return Deferred{Object: j}
+}
+
+// NewDeferred creates a new Deferred, with window.jQuery.Deferred().
+//
+// A factory function that returns a chainable utility object with methods to
+// register multiple callbacks into callback queues, invoke callback queues, and
+// relay the success or failure state of any synchronous or asynchronous
+// function.
+func NewDeferred(i ...interface{}) Deferred {
+	return WrapDeferred(js.Global.Get("window").Get("jQuery").Get("Deferred").New(i...))
+}
+
+// DeferredAPI is the interface of the methods of Deferred.
+type DeferredAPI interface {
+	Done(doneCallbacks *js.Object) Deferred
+}
+
+var _ DeferredAPI = Deferred{}
+
+// Event wraps a jQuery event object, normalized according to W3C standards.
+//
+// See https://api.jquery.com/category/events/event-object/
+type Event struct {
+	*js.Object
+	// The mouse position relative to the left edge of the document.
+	PageX float64 `js:"pageX"`
+	// The DOM element that initiated the event.
+	Target Element `js:"target"`
+	// For key or mouse events, this property indicates the specific key or button
+	// that was pressed.
+	Which int `js:"which"`
+}
+
+// WrapEvent returns the Event wrapping an existing javascript object.
+func WrapEvent(j *js.Object) Event {
+	return Event{Object: j}
+}
+
+// NewEvent creates a new jQuery event object, to be triggered.
+//
+// See https://api.jquery.com/category/events/event-object/
+func NewEvent(src string) Event {
+	return WrapEvent(js.Global.Get("window").Get("jQuery").Get("Event").New(src))
+}
+
+// EventAPI is the interface of the methods of Event.
+type EventAPI interface{}
+
+var _ EventAPI = Event{}
+
+// JqXHR wraps the jQuery XMLHttpRequest object, as returned by jQuery.ajax().
+// It is a Deferred.
+//
+// See https://api.jquery.com/jQuery.ajax/#jqXHR
+type JqXHR struct {
+	*js.Object
+	Deferred
+}
+
+// WrapJqXHR returns the JqXHR wrapping an existing javascript object.
+func WrapJqXHR(j *js.Object) JqXHR {
+	return JqXHR{Object: j, Deferred: WrapDeferred(j)}
+}
+
+// JqXHRAPI is the interface of the methods of JqXHR.
+type JqXHRAPI interface {
+	DeferredAPI
+}
+
+var _ JqXHRAPI = JqXHR{}
+
+// jsTypeOf returns the js typeof of 'j', "null" for null.
+func jsTypeOf(j *js.Object) string {
+	if j == nil {
+		return "null"
+	}
+	if j == js.Undefined {
+		return "undefined"
+	}
+	switch j.Interface().(type) {
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case func(...interface{}) *js.Object:
+		return "function"
+	}
+	return "object"
+}
+
+// BooleanOrNumberOrString is either a Boolean, or a Number, or a String.
+//
+// Use a type switch to get the actual value.
+type BooleanOrNumberOrString interface {
+	isBooleanOrNumberOrString()
+}
+
+func (Boolean) isBooleanOrNumberOrString() {
+}
+
+func (Number) isBooleanOrNumberOrString() {
+}
+
+func (String) isBooleanOrNumberOrString() {
+}
+
+func newBooleanOrNumberOrString(j *js.Object) BooleanOrNumberOrString {
+	switch jsTypeOf(j) {
+	case "boolean":
+		return Boolean(j.Bool())
+	case "number":
+		return Number(j.Float())
+	case "string":
+		return String(j.String())
+	}
+	return nil
+}
+
+// Boolean is a js boolean, as one of the alternatives of a union result.
+type Boolean bool
+
+// Number is a js number, as one of the alternatives of a union result.
+type Number float64
+
+// String is a js string, as one of the alternatives of a union result.
+type String string
+
+// Adds the specified class(es) to each element in the set of matched elements.
+//
+// It's important to note that this method does not replace a class. It simply
+// adds the class, appending it to any which may already be assigned to the
+// elements. See [.removeClass()].
+//
+//	$( "p" ).addClass( "myClass yourClass" );
+//
+// [.removeClass()]: https://api.jquery.com/removeClass/
+func (x JQuery) AddClass(className string) JQuery {
+	x.Call("addClass", className)
+	return x
+}
+
+// Get the value of an attribute for the first element in the set of matched
+// elements.
+func (x JQuery) Attr(attributeName string) string {
+	return x.Call("attr", attributeName).String()
+}
+
+// Get the computed style properties for the first element in the set of matched
+// elements.
+func (x JQuery) CSS(propertyName string) string {
+	return x.Call("css", propertyName).String()
+}
+
+// Bind an event handler to the "click" JavaScript event, & trigger it.
+func (x JQuery) Click(handler func(Event)) JQuery {
+	x.Call("click", func(j *js.Object) {
+		handler(WrapEvent(j))
+	})
+	return x
+}
+
+// Bind an event handler to the "dblclick" JavaScript event, or trigger that
+// event on an element.
+//
+// Deprecated: since jQuery 3.3.
+func (x JQuery) DblClick(handler func(Event)) JQuery {
+	x.Call("dblclick", func(j *js.Object) {
+		handler(WrapEvent(j))
+	})
+	return x
+}
+
+// Set a timer to delay execution of subsequent items in the queue.
+func (x JQuery) Delay(duration interface{}) JQuery {
+	x.Call("delay", duration)
+	return x
+}
+
+// Display the matched elements by fading them to opaque.
+func (x JQuery) FadeIn(i ...interface{}) JQuery {
+	x.Call("fadeIn", i...)
+	return x
+}
+
+// Get the HTML contents of the first element in the set of matched elements.
+func (x JQuery) HTML() string {
+	return x.Call("html").String()
+}
+
+// Remove an event handler.
+//
+// It removes all the handlers of the events, the go handlers cannot be told
+// apart: name the events with a namespace, like "click.myPlugin", to remove
+// only some of them.
+func (x JQuery) Off(events string) JQuery {
+	x.Call("off", events)
+	return x
+}
+
+// Remove an event handler.
+//
+// It removes the handlers delegated with the selector.
+//
+// It removes all the handlers of the events, the go handlers cannot be told
+// apart: name the events with a namespace, like "click.myPlugin", to remove
+// only some of them.
+func (x JQuery) OffDelegated(events string, selector string) JQuery {
+	x.Call("off", events, selector)
+	return x
+}
+
+// Attach an event handler function for one or more events to the selected
+// elements.
+func (x JQuery) On(events string, handler func(Event)) JQuery {
+	x.Call("on", events, func(j *js.Object) {
+		handler(WrapEvent(j))
+	})
+	return x
+}
+
+// Attach an event handler function for one or more events to the selected
+// elements.
+//
+// The handler is called only for the descendants of the selected elements that
+// match the selector.
+func (x JQuery) OnDelegated(events string, selector string, handler func(Event)) JQuery {
+	x.Call("on", events, selector, func(j *js.Object) {
+		handler(WrapEvent(j))
+	})
+	return x
+}
+
+// Get the value of a property for the first element in the set of matched
+// elements.
+func (x JQuery) Prop(propertyName string) BooleanOrNumberOrString {
+	return newBooleanOrNumberOrString(x.Call("prop", propertyName))
+}
+
+// Get the current vertical position of the scroll bar.
+func (x JQuery) ScrollTop() float64 {
+	return x.Call("scrollTop").Float()
+}
+
+// Get or set attributes.
+func (x JQuery) SetAttr(attributeName string, value interface{}) JQuery {
+	x.Call("attr", attributeName, value)
+	return x
+}
+
+// Set one or more attributes for the set of matched elements.
+func (x JQuery) SetAttrMap(attributes map[string]interface{}) JQuery {
+	x.Call("attr", attributes)
+	return x
+}
+
+// Set one or more CSS properties for the set of matched elements.
+func (x JQuery) SetCSS(propertyName string, value string) JQuery {
+	x.Call("css", propertyName, value)
+	return x
+}
+
+// Set one or more CSS properties for the set of matched elements.
+func (x JQuery) SetCSSMap(properties map[string]interface{}) JQuery {
+	x.Call("css", properties)
+	return x
+}
+
+// Set the HTML contents of each element in the set of matched elements.
+func (x JQuery) SetHTML(htmlString string) JQuery {
+	x.Call("html", htmlString)
+	return x
+}
+
+// Add or remove one or more classes from each element in the set of matched
+// elements, depending on either the class's presence or the value of the state
+// argument.
+func (x JQuery) ToggleClass(i ...interface{}) JQuery {
+	x.Call("toggleClass", i...)
+	return x
+}
+
+// Execute all handlers and behaviors attached to the matched elements for the
+// given event type.
+//
+// The extra parameters are passed along to the handlers, after the event.
+func (x JQuery) Trigger(eventType string, extraParameters ...interface{}) JQuery {
+	x.Call("trigger", eventType, extraParameters)
+	return x
+}
+
+// Get the current value of the first element in the set of matched elements.
+func (x JQuery) Val() *js.Object {
+	return x.Call("val")
+}
+
+// Add handlers to be called when the Deferred object is resolved.
+func (x Deferred) Done(doneCallbacks *js.Object) Deferred {
+	x.Call("done", doneCallbacks)
+	return x
+}
+
+// Perform an asynchronous HTTP (Ajax) request.
+func Ajax(i ...interface{}) JqXHR {
+	return WrapJqXHR(JQ().Call("ajax", i...))
+}
+
+// Remove the whitespace from the beginning and end of a string.
+//
+// Deprecated: since jQuery 3.5.
+func Trim(str string) string {
+	return JQ().Call("trim", str).String()
+}
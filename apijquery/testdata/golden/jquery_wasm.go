@@ -0,0 +1,353 @@
+// Code generated by jquery-gen. DO NOT EDIT.
+
+//go:build js && wasm
+
+package jquery
+
+import (
+	"syscall/js"
+)
+
+// Object is a javascript object.
+type Object = js.Value
+
+const (
+	// EventClick is the name of the "click" event.
+	EventClick = "click"
+	// EventDblClick is the name of the "dblclick" event.
+	EventDblClick = "dblclick"
+)
+
+// JQ returns the jQuery global object, the receiver of the static jQuery
+// functions.
+//
+// It is looked up at each call, so jQuery can be loaded after the package init.
+func JQ() Object {
+	return js.Global().Get("jQuery")
+}
+
+// JQuery wraps a jQuery object: a set of matched DOM elements.
+//
+// See https://api.jquery.com/Types/#jQuery
+type JQuery struct {
+	Object
+}
+
+func newJQuery(j Object) JQuery {
+	return JQuery{Object: j}
+}
+
+// Element wraps a DOM element, like the target of an event.
+//
+// See https://api.jquery.com/Types/#Element
+type Element struct {
+	Object
+}
+
+func newElement(j Object) Element {
+	return Element{Object: j}
+}
+
+// Callbacks wraps a multi-purpose callbacks list object, as returned by
+// jQuery.Callbacks().
+//
+// See https://api.jquery.com/jQuery.Callbacks/
+type Callbacks struct {
+	Object
+}
+
+func newCallbacks(j Object) Callbacks {
+	return Callbacks{Object: j}
+}
+
+// NewCallbacks creates a new Callbacks, with jQuery.Callbacks().
+//
+// A multi-purpose callbacks list object that provides a powerful way to manage
+// callback lists.
+func NewCallbacks(flags string) Callbacks {
+	return newCallbacks(js.Global().Get("jQuery").Get("Callbacks").New(flags))
+}
+
+// Deferred wraps a chainable utility object, as returned by jQuery.Deferred().
+//
+// See https://api.jquery.com/category/deferred-object/
+type Deferred struct {
+	Object
+}
+
+func newDeferred(j Object) Deferred {
+	return Deferred{Object: j}
+}
+
+// NewDeferred creates a new Deferred, with jQuery.Deferred().
+//
+// A factory function that returns a chainable utility object with methods to
+// register multiple callbacks into callback queues, invoke callback queues, and
+// relay the success or failure state of any synchronous or asynchronous
+// function.
+func NewDeferred(i ...interface{}) Deferred {
+	return newDeferred(js.Global().Get("jQuery").Get("Deferred").New(i...))
+}
+
+// Event wraps a jQuery event object, normalized according to W3C standards.
+//
+// See https://api.jquery.com/category/events/event-object/
+type Event struct {
+	Object
+	// The mouse position relative to the left edge of the document.
+	PageX float64 `js:"pageX"`
+	// The DOM element that initiated the event.
+	Target Element `js:"target"`
+	// For key or mouse events, this property indicates the specific key or button
+	// that was pressed.
+	Which int `js:"which"`
+}
+
+func newEvent(j Object) Event {
+	return Event{Object: j, PageX: j.Get("pageX").Float(), Which: j.Get("which").Int()}
+}
+
+// NewEvent creates a new jQuery event object, to be triggered.
+//
+// See https://api.jquery.com/category/events/event-object/
+func NewEvent(src string) Event {
+	return newEvent(js.Global().Get("jQuery").Get("Event").New(src))
+}
+
+// JqXHR wraps the jQuery XMLHttpRequest object, as returned by jQuery.ajax().
+// It is a Deferred.
+//
+// See https://api.jquery.com/jQuery.ajax/#jqXHR
+type JqXHR struct {
+	Object
+	Deferred
+}
+
+func newJqXHR(j Object) JqXHR {
+	return JqXHR{Object: j, Deferred: newDeferred(j)}
+}
+
+// jsTypeOf returns the js typeof of 'j', "null" for null.
+func jsTypeOf(j Object) string {
+	return j.Type().String()
+}
+
+// BooleanOrNumberOrString is either a Boolean, or a Number, or a String.
+//
+// Use a type switch to get the actual value.
+type BooleanOrNumberOrString interface {
+	isBooleanOrNumberOrString()
+}
+
+func (Boolean) isBooleanOrNumberOrString() {
+}
+
+func (Number) isBooleanOrNumberOrString() {
+}
+
+func (String) isBooleanOrNumberOrString() {
+}
+
+func newBooleanOrNumberOrString(j Object) BooleanOrNumberOrString {
+	switch jsTypeOf(j) {
+	case "boolean":
+		return Boolean(j.Bool())
+	case "number":
+		return Number(j.Float())
+	case "string":
+		return String(j.String())
+	}
+	return nil
+}
+
+// Boolean is a js boolean, as one of the alternatives of a union result.
+type Boolean bool
+
+// Number is a js number, as one of the alternatives of a union result.
+type Number float64
+
+// String is a js string, as one of the alternatives of a union result.
+type String string
+
+// Adds the specified class(es) to each element in the set of matched elements.
+//
+// It's important to note that this method does not replace a class. It simply
+// adds the class, appending it to any which may already be assigned to the
+// elements. See [.removeClass()].
+//
+//	$( "p" ).addClass( "myClass yourClass" );
+//
+// [.removeClass()]: https://api.jquery.com/removeClass/
+func (x JQuery) AddClass(className string) JQuery {
+	x.Call("addClass", className)
+	return x
+}
+
+// Get the value of an attribute for the first element in the set of matched
+// elements.
+func (x JQuery) Attr(attributeName string) string {
+	return x.Call("attr", attributeName).String()
+}
+
+// Get the computed style properties for the first element in the set of matched
+// elements.
+func (x JQuery) CSS(propertyName string) string {
+	return x.Call("css", propertyName).String()
+}
+
+// Bind an event handler to the "click" JavaScript event, & trigger it.
+//
+// Each call creates a js function for the go func, that is never released (see
+// js.Func.Release): the go func stays reachable as long as the program runs.
+func (x JQuery) Click(handler func(Event)) JQuery {
+	x.Call("click", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		j := args[0]
+		handler(newEvent(j))
+		return nil
+	}))
+	return x
+}
+
+// Set a timer to delay execution of subsequent items in the queue.
+func (x JQuery) Delay(duration interface{}) JQuery {
+	x.Call("delay", duration)
+	return x
+}
+
+// Display the matched elements by fading them to opaque.
+func (x JQuery) FadeIn(i ...interface{}) JQuery {
+	x.Call("fadeIn", i...)
+	return x
+}
+
+// Get the HTML contents of the first element in the set of matched elements.
+func (x JQuery) HTML() string {
+	return x.Call("html").String()
+}
+
+// Remove an event handler.
+//
+// It removes all the handlers of the events, the go handlers cannot be told
+// apart: name the events with a namespace, like "click.myPlugin", to remove
+// only some of them.
+func (x JQuery) Off(events string) JQuery {
+	x.Call("off", events)
+	return x
+}
+
+// Remove an event handler.
+//
+// It removes the handlers delegated with the selector.
+//
+// It removes all the handlers of the events, the go handlers cannot be told
+// apart: name the events with a namespace, like "click.myPlugin", to remove
+// only some of them.
+func (x JQuery) OffDelegated(events string, selector string) JQuery {
+	x.Call("off", events, selector)
+	return x
+}
+
+// Attach an event handler function for one or more events to the selected
+// elements.
+//
+// Each call creates a js function for the go func, that is never released (see
+// js.Func.Release): the go func stays reachable as long as the program runs.
+func (x JQuery) On(events string, handler func(Event)) JQuery {
+	x.Call("on", events, js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		j := args[0]
+		handler(newEvent(j))
+		return nil
+	}))
+	return x
+}
+
+// Attach an event handler function for one or more events to the selected
+// elements.
+//
+// The handler is called only for the descendants of the selected elements that
+// match the selector.
+//
+// Each call creates a js function for the go func, that is never released (see
+// js.Func.Release): the go func stays reachable as long as the program runs.
+func (x JQuery) OnDelegated(events string, selector string, handler func(Event)) JQuery {
+	x.Call("on", events, selector, js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		j := args[0]
+		handler(newEvent(j))
+		return nil
+	}))
+	return x
+}
+
+// Get the value of a property for the first element in the set of matched
+// elements.
+func (x JQuery) Prop(propertyName string) BooleanOrNumberOrString {
+	return newBooleanOrNumberOrString(x.Call("prop", propertyName))
+}
+
+// Get the current vertical position of the scroll bar.
+func (x JQuery) ScrollTop() float64 {
+	return x.Call("scrollTop").Float()
+}
+
+// Get or set attributes.
+func (x JQuery) SetAttr(attributeName string, value interface{}) JQuery {
+	x.Call("attr", attributeName, value)
+	return x
+}
+
+// Set one or more attributes for the set of matched elements.
+func (x JQuery) SetAttrMap(attributes map[string]interface{}) JQuery {
+	x.Call("attr", attributes)
+	return x
+}
+
+// Set one or more CSS properties for the set of matched elements.
+func (x JQuery) SetCSS(propertyName string, value string) JQuery {
+	x.Call("css", propertyName, value)
+	return x
+}
+
+// Set one or more CSS properties for the set of matched elements.
+func (x JQuery) SetCSSMap(properties map[string]interface{}) JQuery {
+	x.Call("css", properties)
+	return x
+}
+
+// Set the HTML contents of each element in the set of matched elements.
+func (x JQuery) SetHTML(htmlString string) JQuery {
+	x.Call("html", htmlString)
+	return x
+}
+
+// Add or remove one or more classes from each element in the set of matched
+// elements, depending on either the class's presence or the value of the state
+// argument.
+func (x JQuery) ToggleClass(i ...interface{}) JQuery {
+	x.Call("toggleClass", i...)
+	return x
+}
+
+// Execute all handlers and behaviors attached to the matched elements for the
+// given event type.
+//
+// The extra parameters are passed along to the handlers, after the event.
+func (x JQuery) Trigger(eventType string, extraParameters ...interface{}) JQuery {
+	x.Call("trigger", eventType, extraParameters)
+	return x
+}
+
+// Get the current value of the first element in the set of matched elements.
+func (x JQuery) Val() Object {
+	return x.Call("val")
+}
+
+// Add handlers to be called when the Deferred object is resolved.
+func (x Deferred) Done(doneCallbacks Object) Deferred {
+	x.Call("done", doneCallbacks)
+	return x
+}
+
+// Perform an asynchronous HTTP (Ajax) request.
+func Ajax(i ...interface{}) JqXHR {
+	return newJqXHR(JQ().Call("ajax", i...))
+}
@@ -0,0 +1,125 @@
+package apijquery
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+
+	"github.com/ericaro/apigen"
+)
+
+//unionSeparator separates the alternatives of a union type in the jquery doc ("String or Number")
+const unionSeparator = " or "
+
+//canonicalTypes maps the jquery doc types that can be part of a union to their canonical name
+var canonicalTypes = map[string]string{
+	"String": "String", "string": "String", "selector": "String", "Selector": "String", "htmlString": "String",
+	"Number": "Number", "Integer": "Number",
+	"Boolean": "Boolean", "boolean": "Boolean",
+	"jQuery": "jQuery", "Event": "Event", "Callbacks": "Callbacks", "Deferred": "Deferred", "jqXHR": "jqXHR",
+	"Element": "Element",
+}
+
+//typeOfs maps canonical types to their js typeof
+var typeOfs = map[string]string{
+	"String":  "string",
+	"Number":  "number",
+	"Boolean": "boolean",
+}
+
+func typeOf(canonical string) string {
+	if t, exists := typeOfs[canonical]; exists {
+		return t
+	}
+	return "object"
+}
+
+//isUnion returns true if 'name' is a union type
+func isUnion(name string) bool { return strings.Contains(name, unionSeparator) }
+
+//unionName returns the go name of a union type ("Number or String" -> "NumberOrString")
+func unionName(name string) string {
+	parts := strings.Split(name, unionSeparator)
+	for i, p := range parts {
+		parts[i] = GoName(p)
+	}
+	return strings.Join(parts, "Or")
+}
+
+//canonicalReturn normalizes a return type that might be a union ("String or Number").
+//
+// alternatives are canonicalized, deduplicated and sorted, "undefined" is dropped (it's a nil union).
+// It returns "Object" when alternatives cannot be told apart at runtime (see apigen.Union)
+func canonicalReturn(ret string) string {
+	if !isUnion(ret) {
+		return ret
+	}
+	seen := make(map[string]bool)    // canonical types already in
+	typeofs := make(map[string]bool) // typeof already in
+	parts := make([]string, 0, 3)
+	for _, p := range strings.Split(ret, unionSeparator) {
+		p = strings.TrimSpace(p)
+		c, exists := canonicalTypes[p]
+		switch {
+		case p == "undefined" || seen[c]:
+			continue
+		case !exists || typeofs[typeOf(c)]:
+			return "Object" // cannot tell them apart
+		}
+		seen[c], typeofs[typeOf(c)] = true, true
+		parts = append(parts, c)
+	}
+	switch len(parts) {
+	case 0:
+		return "undefined"
+	case 1:
+		return parts[0]
+	default:
+		sort.Strings(parts)
+		return strings.Join(parts, unionSeparator)
+	}
+}
+
+//union builds the apigen.Union for a canonical union type
+func (c Compiler) union(name string) *apigen.Union {
+	parts := strings.Split(name, unionSeparator)
+	u := &apigen.Union{
+		Name:         unionName(name),
+		Alternatives: make([]*apigen.Alternative, len(parts)),
+	}
+	names := make([]string, len(parts))
+	for i, p := range parts {
+		u.Alternatives[i] = c.alternative(p)
+		names[i] = u.Alternatives[i].Name
+	}
+	u.Description = u.Name + " is either a " + strings.Join(names, ", or a ") + ".\n\nUse a type switch to get the actual value."
+	return u
+}
+
+//alternative builds the apigen.Alternative for a canonical type
+func (c Compiler) alternative(canonical string) *apigen.Alternative {
+	a := &apigen.Alternative{
+		Name:   GoName(canonical),
+		TypeOf: typeOf(canonical),
+	}
+	var primitive func(ast.Expr) ast.Expr // converter to the primitive go type
+	switch canonical {
+	case "String":
+		a.Type, primitive = &ast.Ident{Name: "string"}, apigen.StringConverter
+	case "Number":
+		a.Type, primitive = &ast.Ident{Name: "float64"}, apigen.FloatConverter
+	case "Boolean":
+		a.Type, primitive = &ast.Ident{Name: "bool"}, apigen.BoolConverter
+	default: // a generated type
+		a.Convert = c.converterFor(canonical)
+		return a
+	}
+	a.Description = a.Name + " is a js " + a.TypeOf + ", as one of the alternatives of a union result."
+	a.Convert = func(j ast.Expr) ast.Expr {
+		return &ast.CallExpr{
+			Fun:  &ast.Ident{Name: a.Name},
+			Args: []ast.Expr{primitive(j)},
+		}
+	}
+	return a
+}
@@ -0,0 +1,76 @@
+//go:build js && !wasm
+
+package apigen
+
+import (
+	"testing"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// run with: gopherjs test -bench Chain github.com/ericaro/apigen
+//
+// chained mimics a generated type, to compare the two styles of chainable methods generated by FuncDecl.
+type chained struct {
+	*js.Object
+}
+
+func newChained(j *js.Object) chained { return chained{Object: j} }
+
+// wrapped is a method that wraps the call result
+func (x chained) wrapped() chained { return newChained(x.Call("self")) }
+
+// receiver is a method that returns the receiver (see Func.ReturnsReceiver)
+func (x chained) receiver() chained {
+	x.Call("self")
+	return x
+}
+
+func newChainedObject() chained {
+	return newChained(js.Global.Call("eval", "({self: function() { return this; }})"))
+}
+
+func BenchmarkChainWrapped(b *testing.B) {
+	x := newChainedObject()
+	for i := 0; i < b.N; i++ {
+		x = x.wrapped().wrapped().wrapped().wrapped()
+	}
+}
+
+func BenchmarkChainReceiver(b *testing.B) {
+	x := newChainedObject()
+	for i := 0; i < b.N; i++ {
+		x = x.receiver().receiver().receiver().receiver()
+	}
+}
+
+// deferred and jqXHR mimic a generated type and its parent: the parent is wrapped from the same
+// *js.Object (see Ctor).
+type deferred struct {
+	*js.Object
+}
+
+func newDeferred(j *js.Object) deferred { return deferred{Object: j} }
+
+func (x deferred) state() string { return x.Call("state").String() }
+
+type jqXHR struct {
+	*js.Object
+	deferred
+}
+
+func newJqXHR(j *js.Object) jqXHR { return jqXHR{Object: j, deferred: newDeferred(j)} }
+
+func TestParentMethod(t *testing.T) {
+	x := newJqXHR(js.Global.Call("eval", `({state: function() { return "resolved"; }, status: 200})`))
+	if s := x.state(); s != "resolved" {
+		t.Errorf("state() = %q, want resolved", s)
+	}
+	// the promoted Object is the one of the type, shared with its parent
+	if x.Object != x.deferred.Object {
+		t.Errorf("the parent wraps another object")
+	}
+	if s := x.Get("status").Int(); s != 200 {
+		t.Errorf("status = %v, want 200", s)
+	}
+}
@@ -0,0 +1,93 @@
+package apigen
+
+import (
+	"go/ast"
+	"strings"
+)
+
+//CommentWidth is the column at which Doc wraps the description text
+var CommentWidth = 80
+
+//Doc turns a free text description into a comment group.
+//
+// Paragraphs are separated by blank lines, and wrapped at CommentWidth.
+// Lines starting with a space or a tab (code blocks, lists) are kept verbatim.
+//
+// It returns nil for an empty description.
+func Doc(text string) *ast.CommentGroup {
+	lines := make([]string, 0, 10)
+	blank := true // true when the last line is a blank one (or there is none yet)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		switch {
+		case line == "":
+			if !blank {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+
+		case line[0] == ' ' || line[0] == '\t': // preformatted
+			lines = append(lines, line)
+
+		default:
+			lines = append(lines, wrap(line, CommentWidth-3)...) // 3 for "// "
+		}
+		blank = false
+	}
+	if blank && len(lines) > 0 { // remove the trailing blank line
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	g := &ast.CommentGroup{List: make([]*ast.Comment, len(lines))}
+	for i, line := range lines {
+		switch {
+		case line == "":
+			line = "//"
+		case line[0] == '\t':
+			line = "//" + line
+		default:
+			line = "// " + line
+		}
+		g.List[i] = &ast.Comment{Text: line}
+	}
+	return g
+}
+
+//wrap splits a single line into lines of at most width bytes, breaking on spaces.
+//
+// words longer than width are never broken.
+func wrap(line string, width int) (lines []string) {
+	current := ""
+	for _, word := range strings.Fields(line) {
+		switch {
+		case current == "":
+			current = word
+		case len(current)+1+len(word) > width:
+			lines = append(lines, current)
+			current = word
+		default:
+			current += " " + word
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return
+}
+
+//AppendDoc appends the paragraph 'text' to the comment group 'g' (see Doc)
+func AppendDoc(g *ast.CommentGroup, text string) *ast.CommentGroup {
+	doc := Doc(text)
+	switch {
+	case g == nil:
+		return doc
+	case doc == nil:
+		return g
+	}
+	list := append(append([]*ast.Comment{}, g.List...), &ast.Comment{Text: "//"})
+	return &ast.CommentGroup{List: append(list, doc.List...)}
+}
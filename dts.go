@@ -0,0 +1,149 @@
+package apigen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"strconv"
+	"strings"
+)
+
+//DTS generates the typescript declarations of the javascript api wrapped by 'api'.
+//
+// The declarations are in a namespace named after the package, so that they do not merge with the
+// globals of the DOM library (like Event). Types become interfaces of their js properties and methods
+// (extending their parents), unions become type aliases, and the funcs called on a global var (like
+// JQ) become the methods of the interface of that global ("JQStatic"), declared as a const with its
+// js name. Go types are mapped back to typescript types ("any" for *js.Object and interface{}), and
+// descriptions to JSDoc comments.
+func DTS(api *Api) []byte {
+	types := make(map[string]bool)
+	for _, t := range api.Types {
+		types[t.Name] = true
+	}
+	for _, u := range api.Unions {
+		types[u.Name] = true
+	}
+	var buf bytes.Buffer
+	buf.WriteString(header(api.Generator))
+
+	var consts []string // the global vars, outside of the namespace
+	fmt.Fprintf(&buf, "declare namespace %s {\n", api.Name)
+	for _, t := range api.Types {
+		fprintJSDoc(&buf, "\t", t.Description)
+		fmt.Fprintf(&buf, "\tinterface %s", t.Name)
+		if len(t.Parents) > 0 {
+			parents := make([]string, len(t.Parents))
+			for i, p := range t.Parents {
+				parents[i] = p.Name
+			}
+			fmt.Fprintf(&buf, " extends %s", strings.Join(parents, ", "))
+		}
+		fmt.Fprintln(&buf, " {")
+		for _, p := range t.Properties {
+			fprintJSDoc(&buf, "\t\t", p.Description)
+			fmt.Fprintf(&buf, "\t\t%s: %s;\n", p.JS, tsType(p.Type, types))
+		}
+		for _, f := range api.Funcs {
+			if f.ReceiverType != nil && receiverName(f.ReceiverType) == t.Name {
+				fprintTSMethod(&buf, f, types)
+			}
+		}
+		fmt.Fprintf(&buf, "\t}\n\n")
+	}
+
+	for _, u := range api.Unions {
+		alternatives := make([]string, len(u.Alternatives))
+		for i, a := range u.Alternatives {
+			alternatives[i] = tsAlternative(a, types)
+		}
+		fprintJSDoc(&buf, "\t", u.Description)
+		fmt.Fprintf(&buf, "\ttype %s = %s;\n\n", u.Name, strings.Join(alternatives, " | "))
+	}
+
+	for _, v := range api.Vars {
+		path, ok := globalPath(v.Value)
+		if !ok || path == "" {
+			continue
+		}
+		static := v.Name + "Static"
+		fprintJSDoc(&buf, "\t", v.Description)
+		fmt.Fprintf(&buf, "\tinterface %s {\n", static)
+		for _, t := range api.Types {
+			if c := t.Constructor; c != nil && c.JS == path+"."+lastName(c.JS) {
+				fprintJSDoc(&buf, "\t\t", c.Description)
+				fmt.Fprintf(&buf, "\t\t%s: new (%s) => %s;\n", lastName(c.JS), tsParams(c.Params, types), t.Name)
+			}
+		}
+		for _, f := range api.Funcs {
+			if f.ReceiverType == nil && f.ReceiverName == v.Name {
+				fprintTSMethod(&buf, f, types)
+			}
+		}
+		fmt.Fprintf(&buf, "\t}\n\n")
+		consts = append(consts, fmt.Sprintf("declare const %s: %s.%s;\n", lastName(path), api.Name, static))
+	}
+	buf.Truncate(len(bytes.TrimRight(buf.Bytes(), "\n")))
+	fmt.Fprintf(&buf, "\n}\n")
+	for _, c := range consts {
+		fmt.Fprintf(&buf, "\n%s", c)
+	}
+	return buf.Bytes()
+}
+
+//fprintTSMethod prints the typescript declaration of the method 'f', with its js name
+func fprintTSMethod(buf *bytes.Buffer, f *Func, types map[string]bool) {
+	doc := f.Description
+	if f.Deprecated != "" {
+		doc = strings.TrimSpace(doc + "\n\n@deprecated " + f.Deprecated)
+	}
+	fprintJSDoc(buf, "\t\t", doc)
+	fmt.Fprintf(buf, "\t\t%s(%s): %s;\n", f.JS, tsParams(jsParams(f), types), tsResult(f, types))
+}
+
+//tsAlternative returns the typescript type of a union alternative
+func tsAlternative(a *Alternative, types map[string]bool) string {
+	switch {
+	case a.Type != nil:
+		return tsType(a.Type, types)
+	case types[a.Name]:
+		return a.Name
+	case a.TypeOf == "function":
+		return "Function"
+	case a.TypeOf == "object":
+		return "object"
+	default:
+		return a.TypeOf
+	}
+}
+
+//globalPath returns the js path of a Global expression ("foo.Bar" for js.Global.Get("foo").Get("Bar"))
+func globalPath(e ast.Expr) (string, bool) {
+	if isJSGlobal(e) {
+		return "", true
+	}
+	call, ok := e.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	lit, isLit := call.Args[0].(*ast.BasicLit)
+	if !ok || !isLit || sel.Sel.Name != "Get" {
+		return "", false
+	}
+	name, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	path, ok := globalPath(sel.X)
+	if !ok {
+		return "", false
+	}
+	if path == "" {
+		return name, true
+	}
+	return path + "." + name, true
+}
+
+//lastName returns the last name of a js path ("Bar" for "foo.Bar")
+func lastName(path string) string { return path[strings.LastIndex(path, ".")+1:] }
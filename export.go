@@ -0,0 +1,260 @@
+package apigen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The reverse mode exposes go funcs to javascript: the api describes go funcs (see ParseExports),
+// ExportSource generates the code registering them in a js global object, and ExportDTS the
+// typescript declarations of that object.
+
+//ExportFunc is the name of the generated func registering the go funcs in javascript
+const ExportFunc = "ExportJS"
+
+//ParseExports parses the exported funcs, types and methods of the package 'pkg' in 'dir' into an api.
+//
+// Generated and test files are ignored, and so are generic declarations and funcs with more than
+// one result. The funcs are exposed with a lower case first letter ("add" for Add), the methods
+// with their go name.
+func ParseExports(dir, pkg string) (*Api, error) {
+	filter := func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, filter, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	p, exists := pkgs[pkg]
+	if !exists {
+		return nil, fmt.Errorf("no package %s in %s", pkg, dir)
+	}
+	names := make([]string, 0, len(p.Files))
+	for name := range p.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	api := &Api{Name: pkg}
+	for _, name := range names {
+		if ast.IsGenerated(p.Files[name]) {
+			continue
+		}
+		api.addExports(p.Files[name])
+	}
+	return api, nil
+}
+
+//addExports adds the exported declarations of 'file' to the api
+func (api *Api) addExports(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				if !spec.Name.IsExported() || spec.TypeParams != nil {
+					continue
+				}
+				doc := spec.Doc
+				if doc == nil && len(decl.Specs) == 1 {
+					doc = decl.Doc
+				}
+				api.Types = append(api.Types, &Type{Name: spec.Name.Name, Description: doc.Text()})
+			}
+
+		case *ast.FuncDecl:
+			t := decl.Type
+			if !decl.Name.IsExported() || t.TypeParams != nil {
+				continue
+			}
+			if t.Results != nil && (len(t.Results.List) > 1 || len(t.Results.List) == 1 && len(t.Results.List[0].Names) > 1) {
+				continue
+			}
+			f := &Func{
+				Description: decl.Doc.Text(),
+				Name:        decl.Name.Name,
+				JS:          lowerFirst(decl.Name.Name),
+				Params:      t.Params,
+			}
+			if t.Results != nil && len(t.Results.List) == 1 {
+				f.ResultType = t.Results.List[0].Type
+			}
+			if decl.Recv != nil && len(decl.Recv.List) > 0 {
+				recv := decl.Recv.List[0]
+				if !ast.IsExported(receiverName(recv.Type)) {
+					continue
+				}
+				f.ReceiverType, f.ReceiverName, f.JS = recv.Type, "x", f.Name
+				if len(recv.Names) > 0 {
+					f.ReceiverName = recv.Names[0].Name
+				}
+			}
+			api.Funcs = append(api.Funcs, f)
+		}
+	}
+}
+
+//lowerFirst returns 'name' with a lower case first letter
+func lowerFirst(name string) string {
+	r, n := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[n:]
+}
+
+//exportConverter returns the conversion of a js argument to the go param type 'typ' (nil if there is none)
+func exportConverter(typ ast.Expr) func(ast.Expr) ast.Expr {
+	if it, ok := typ.(*ast.InterfaceType); ok && len(it.Methods.List) == 0 {
+		return InterfaceConverter
+	}
+	if isIdent(typ, "any") {
+		return InterfaceConverter
+	}
+	return propertyConverter(typ)
+}
+
+//Exportable returns true if the func 'f' can be registered by ExportDecl.
+//
+// it must be a func (methods are exposed by js.MakeWrapper), with params of a basic type,
+// interface{}, or *js.Object.
+func Exportable(f *Func) bool {
+	if f.ReceiverType != nil {
+		return false
+	}
+	if f.Params == nil {
+		return true
+	}
+	for _, p := range f.Params.List {
+		if exportConverter(p.Type) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+//ExportDecl declares the func registering the exportable funcs of the api in the js global object 'namespace'
+//
+//	func ExportJS() {
+//		o := js.Global.Get("Object").New()
+//		o.Set("add", func(a *js.Object, b *js.Object) interface{} {
+//			return Add(a.Int(), b.Int())
+//		})
+//		js.Global.Set("svc", o)
+//	}
+//
+// the results of a type of the api are wrapped by js.MakeWrapper, so that their methods can be called from js.
+func ExportDecl(api *Api, namespace string) *ast.FuncDecl {
+	types := make(map[string]bool)
+	for _, t := range api.Types {
+		types[t.Name] = true
+	}
+	str := func(s string) ast.Expr { return &ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", s)} }
+	global := &ast.SelectorExpr{X: &ast.Ident{Name: "js"}, Sel: &ast.Ident{Name: "Global"}}
+	call := func(x ast.Expr, method string, args ...ast.Expr) *ast.CallExpr {
+		return &ast.CallExpr{Fun: &ast.SelectorExpr{X: x, Sel: &ast.Ident{Name: method}}, Args: args}
+	}
+	o := &ast.Ident{Name: "o"}
+
+	body := []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs: []ast.Expr{o},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{call(call(global, "Get", str("Object")), "New")},
+		},
+	}
+	for _, f := range api.Funcs {
+		if !Exportable(f) {
+			continue
+		}
+		params := &ast.FieldList{}
+		goCall := &ast.CallExpr{Fun: &ast.Ident{Name: f.Name}}
+		if f.Params != nil {
+			for _, p := range f.Params.List {
+				names := p.Names
+				if len(names) == 0 {
+					names = []*ast.Ident{&ast.Ident{Name: fmt.Sprintf("arg%d", len(goCall.Args))}}
+				}
+				for _, n := range names {
+					arg := &ast.Ident{Name: n.Name}
+					params.List = append(params.List, &ast.Field{Names: []*ast.Ident{arg}, Type: JSObject})
+					goCall.Args = append(goCall.Args, exportConverter(p.Type)(arg))
+				}
+			}
+		}
+		lit := &ast.FuncLit{Type: &ast.FuncType{Params: params}, Body: &ast.BlockStmt{}}
+		if f.ResultType == nil {
+			lit.Body.List = []ast.Stmt{&ast.ExprStmt{X: goCall}}
+		} else {
+			var result ast.Expr = goCall
+			if types[receiverName(f.ResultType)] {
+				result = call(&ast.Ident{Name: "js"}, "MakeWrapper", goCall)
+			}
+			lit.Type.Results = &ast.FieldList{List: []*ast.Field{&ast.Field{Type: EmptyInterface()}}}
+			lit.Body.List = []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{result}}}
+		}
+		body = append(body, &ast.ExprStmt{X: call(o, "Set", str(f.JS), lit)})
+	}
+	body = append(body, &ast.ExprStmt{X: call(global, "Set", str(namespace), o)})
+
+	return &ast.FuncDecl{
+		Doc:  Doc(fmt.Sprintf("%s registers the funcs of the package in javascript, as the global object %q.", ExportFunc, namespace)),
+		Name: &ast.Ident{Name: ExportFunc},
+		Type: &ast.FuncType{Params: &ast.FieldList{}},
+		Body: &ast.BlockStmt{List: body},
+	}
+}
+
+//ExportSource generates the formatted go source of the registration of the api in the js global
+// object 'namespace' (see ExportDecl), for gopherjs.
+func ExportSource(api *Api, namespace string) ([]byte, error) {
+	file := &ast.File{
+		Name:  &ast.Ident{Name: api.Name},
+		Decls: []ast.Decl{ImportDecl([]string{GopherJS.Import}), ExportDecl(api, namespace)},
+	}
+	return fileSource(api.Generator, file, GopherJS.Constraint)
+}
+
+//ExportDTS generates the typescript declarations of the js global object 'namespace' registered by
+// ExportSource, and of the types of the api, with their methods. Descriptions become JSDoc comments.
+func ExportDTS(api *Api, namespace string) []byte {
+	types := make(map[string]bool)
+	for _, t := range api.Types {
+		types[t.Name] = true
+	}
+	var buf bytes.Buffer
+	buf.WriteString(header(api.Generator))
+
+	fmt.Fprintf(&buf, "declare namespace %s {\n", namespace)
+	for _, f := range api.Funcs {
+		if !Exportable(f) {
+			continue
+		}
+		fprintJSDoc(&buf, "\t", f.Description)
+		fmt.Fprintf(&buf, "\tfunction %s(%s): %s;\n", f.JS, tsParams(f.Params, types), tsResult(f, types))
+	}
+	fmt.Fprintln(&buf, "}")
+
+	for _, t := range api.Types {
+		fmt.Fprintln(&buf)
+		fprintJSDoc(&buf, "", t.Description)
+		fmt.Fprintf(&buf, "interface %s {\n", t.Name)
+		for _, f := range api.Funcs {
+			if f.ReceiverType == nil || receiverName(f.ReceiverType) != t.Name {
+				continue
+			}
+			fprintJSDoc(&buf, "\t", f.Description)
+			fmt.Fprintf(&buf, "\t%s(%s): %s;\n", f.JS, tsParams(f.Params, types), tsResult(f, types))
+		}
+		fmt.Fprintln(&buf, "}")
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,75 @@
+package apigen
+
+import (
+	"fmt"
+	"os"
+)
+
+func ExampleExportSource() {
+	api, err := ParseExports("testdata/export", "svc")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	src, err := ExportSource(api, "svc")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	os.Stdout.Write(src)
+	//Output:
+	// // Code generated by apigen. DO NOT EDIT.
+	//
+	// //go:build js && !wasm
+	//
+	// package svc
+	//
+	// import (
+	// 	"github.com/gopherjs/gopherjs/js"
+	// )
+	//
+	// // ExportJS registers the funcs of the package in javascript, as the global
+	// // object "svc".
+	// func ExportJS() {
+	// 	o := js.Global.Get("Object").New()
+	// 	o.Set("newCounter", func(n *js.Object) interface{} {
+	// 		return js.MakeWrapper(NewCounter(n.Int()))
+	// 	})
+	// 	o.Set("join", func(words *js.Object, sep *js.Object) interface{} {
+	// 		return Join(words.Interface(), sep.String())
+	// 	})
+	// 	js.Global.Set("svc", o)
+	// }
+}
+
+func ExampleExportDTS() {
+	api, err := ParseExports("testdata/export", "svc")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	os.Stdout.Write(ExportDTS(api, "svc"))
+	//Output:
+	// // Code generated by apigen. DO NOT EDIT.
+	//
+	// declare namespace svc {
+	// 	/**
+	// 	 * NewCounter returns a counter starting at 'n'.
+	// 	 */
+	// 	function newCounter(n: number): Counter;
+	// 	/**
+	// 	 * Join joins the words.
+	// 	 */
+	// 	function join(words: any, sep: string): string;
+	// }
+	//
+	// /**
+	//  * Counter counts things.
+	//  */
+	// interface Counter {
+	// 	/**
+	// 	 * Inc increments the counter by 'step', and returns the new count.
+	// 	 */
+	// 	Inc(step: number): number;
+	// }
+}
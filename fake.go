@@ -0,0 +1,67 @@
+package apigen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+//RewriteFake rewrites 'file' for the in-memory js package of jsfake: the wrappers copy the
+// properties (see CopyProperties), jsTypeOf(j) is js.TypeOf(j), and each type gets a recording
+// implementation (see FakeDecl).
+func RewriteFake(api *Api, file *ast.File) {
+	CopyProperties(api, file)
+	typeOfBody(file, &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: &ast.Ident{Name: "js"}, Sel: &ast.Ident{Name: "TypeOf"}},
+		Args: []ast.Expr{&ast.Ident{Name: "j"}},
+	})
+	for _, t := range api.Types {
+		for i, d := range file.Decls {
+			if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.Name == CtorName(t) {
+				decls := append([]ast.Decl{FakeDecl(t)}, file.Decls[i+1:]...)
+				file.Decls = append(file.Decls[:i+1], decls...)
+				break
+			}
+		}
+	}
+}
+
+//FakeName returns the name of the func creating a fake of the type 'j' (FakeFoo)
+func FakeName(j *Type) string { return "Fake" + j.Name }
+
+//FakeDecl declares the func wrapping a new fake js object into a 'j', it returns both: the object
+// records the calls of the methods of 'j', and returns their configured results.
+//
+//	func FakeFoo() (Foo, Object) {
+//		o := new(js.Object)
+//		return newFoo(o), o
+//	}
+func FakeDecl(j *Type) *ast.FuncDecl {
+	o := &ast.Ident{Name: "o"}
+	return &ast.FuncDecl{
+		Doc: Doc(fmt.Sprintf("%s wraps a new fake js object, that records the calls of the methods and returns their "+
+			"configured results: it returns the %s and the object.", FakeName(j), j.Name)),
+		Name: &ast.Ident{Name: FakeName(j)},
+		Type: &ast.FuncType{
+			Params: &ast.FieldList{},
+			Results: &ast.FieldList{List: []*ast.Field{
+				{Type: &ast.Ident{Name: j.Name}},
+				{Type: &ast.Ident{Name: ObjectAlias}},
+			}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{o},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{&ast.CallExpr{
+					Fun:  &ast.Ident{Name: "new"},
+					Args: []ast.Expr{&ast.SelectorExpr{X: &ast.Ident{Name: "js"}, Sel: &ast.Ident{Name: "Object"}}},
+				}},
+			},
+			&ast.ReturnStmt{Results: []ast.Expr{
+				&ast.CallExpr{Fun: &ast.Ident{Name: CtorName(j)}, Args: []ast.Expr{o}},
+				o,
+			}},
+		}},
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"strings"
 )
 
 func File(api *Api) (file *ast.File) {
@@ -16,12 +17,61 @@ func File(api *Api) (file *ast.File) {
 	//add the imports
 	file.Decls = append(file.Decls, ImportDecl(api.Imports))
 
+	if len(api.Consts) > 0 {
+		file.Decls = append(file.Decls, ConstDecl(api.Consts))
+	}
+	vars := make([]*Var, 0, len(api.Vars))
+	lazy := make(map[string]bool)
+	for _, v := range api.Vars {
+		if v.Lazy {
+			lazy[v.Name] = true
+		} else {
+			vars = append(vars, v)
+		}
+	}
+	if len(vars) > 0 {
+		file.Decls = append(file.Decls, VarDecl(vars))
+	}
+	for _, v := range api.Vars {
+		if v.Lazy {
+			file.Decls = append(file.Decls, LazyVarDecl(v))
+		}
+	}
+
 	for _, ty := range api.Types {
 		file.Decls = append(file.Decls, TypeDecl(ty))
-		file.Decls = append(file.Decls, Ctor(ty))
+		if !ty.handCtor {
+			file.Decls = append(file.Decls, Ctor(ty))
+		}
+		if ty.Constructor != nil {
+			file.Decls = append(file.Decls, ConstructorDecl(ty))
+		}
+		if api.Interfaces {
+			file.Decls = append(file.Decls, InterfaceDecls(ty, api.Funcs)...)
+		}
+	}
+
+	if len(api.Unions) > 0 {
+		file.Decls = append(file.Decls, TypeOfDecl())
+	}
+	declared := make(map[string]bool) // alternatives are shared, declare them once
+	for _, u := range api.Unions {
+		file.Decls = append(file.Decls, UnionDecls(u)...)
+		for _, a := range u.Alternatives {
+			if d := AlternativeDecl(a); d != nil && !declared[a.Name] {
+				declared[a.Name] = true
+				file.Decls = append(file.Decls, d)
+			}
+		}
 	}
 
 	for _, f := range api.Funcs {
+		if f.ReceiverType == nil && lazy[f.ReceiverName] {
+			// a copy: File does not modify the api
+			c := *f
+			c.lazyReceiver = true
+			f = &c
+		}
 		file.Decls = append(file.Decls, FuncDecl(f))
 	}
 	return
@@ -45,9 +95,59 @@ func ImportDecl(imports []string) (impDecl *ast.GenDecl) {
 	return
 }
 
+//ConstDecl generates a single const block for all the constants
+func ConstDecl(consts []*Const) (g *ast.GenDecl) {
+	g = &ast.GenDecl{
+		Tok:    token.CONST,
+		Lparen: token.Pos(1),
+		Specs:  make([]ast.Spec, len(consts)),
+	}
+	for i, c := range consts {
+		g.Specs[i] = &ast.ValueSpec{
+			Doc:    Doc(c.Description),
+			Names:  []*ast.Ident{&ast.Ident{Name: c.Name}},
+			Values: []ast.Expr{c.Value},
+		}
+	}
+	return
+}
+
+//VarDecl generates a single var block for all the variables
+func VarDecl(vars []*Var) (g *ast.GenDecl) {
+	g = &ast.GenDecl{
+		Tok:    token.VAR,
+		Lparen: token.Pos(1),
+		Specs:  make([]ast.Spec, len(vars)),
+	}
+	for i, v := range vars {
+		g.Specs[i] = &ast.ValueSpec{
+			Doc:    Doc(v.Description),
+			Names:  []*ast.Ident{&ast.Ident{Name: v.Name}},
+			Values: []ast.Expr{v.Value},
+		}
+	}
+	return
+}
+
+//LazyVarDecl declares a Lazy var as a func returning its value
+func LazyVarDecl(v *Var) *ast.FuncDecl {
+	return &ast.FuncDecl{
+		Doc:  Doc(v.Description),
+		Name: &ast.Ident{Name: v.Name},
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{},
+			Results: &ast.FieldList{List: []*ast.Field{&ast.Field{Type: JSObject}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.ReturnStmt{Results: []ast.Expr{v.Value}},
+		}},
+	}
+}
+
 //Field convert a Property into an ast.Field
 func Field(p *Property) (f *ast.Field) {
 	f = new(ast.Field)
+	f.Doc = Doc(p.Description)
 	f.Names = []*ast.Ident{&ast.Ident{Name: p.Name}}
 	f.Type = p.Type
 	f.Tag = &ast.BasicLit{
@@ -60,6 +160,7 @@ func Field(p *Property) (f *ast.Field) {
 func TypeDecl(ty *Type) (g *ast.GenDecl) {
 	g = new(ast.GenDecl)
 	g.Tok = token.TYPE
+	g.Doc = Doc(ty.Description)
 
 	tspec := new(ast.TypeSpec)
 	g.Specs = []ast.Spec{tspec}
@@ -69,21 +170,203 @@ func TypeDecl(ty *Type) (g *ast.GenDecl) {
 	tspec.Type = st
 
 	st.Fields = new(ast.FieldList)
-	st.Fields.List = make([]*ast.Field, 0, 1+len(ty.Properties))
+	st.Fields.List = make([]*ast.Field, 0, 1+len(ty.Parents)+len(ty.Properties))
 
 	//always prepend the anonymous *js.Object
 	st.Fields.List = append(st.Fields.List, &ast.Field{Type: JSObject})
 
+	//then the parents, sharing the same *js.Object (see Ctor)
+	for _, p := range ty.Parents {
+		st.Fields.List = append(st.Fields.List, &ast.Field{Type: &ast.Ident{Name: p.Name}})
+	}
+
 	for _, p := range ty.Properties {
 		st.Fields.List = append(st.Fields.List, Field(p))
 	}
 	return
 }
 
+//CtorName returns the name of the function that wraps a *js.Object into the type
+func CtorName(j *Type) string { return WrapperName(j.Name, j.Exported) }
+
+//WrapperName returns the name of the function that wraps a *js.Object into the type 'name'
+//
+// WrapFoo when exported, newFoo otherwise
+func WrapperName(name string, exported bool) string {
+	if exported {
+		return "Wrap" + name
+	}
+	return "new" + name
+}
+
+//ConstructorName returns the name of the go function calling the js constructor of the type
+func ConstructorName(j *Type) string {
+	if j.Constructor == nil || j.Constructor.Name == "" {
+		return "New" + j.Name
+	}
+	return j.Constructor.Name
+}
+
+//InterfaceName returns the name of the interface of the methods of the type
+func InterfaceName(j *Type) string { return j.Name + "API" }
+
+//InterfaceDecls declares the interface of the methods of the type 'j' among 'funcs', and asserts
+// that the type implements it:
+//
+//	type FooAPI interface {
+//		BarAPI // the interface of each parent
+//		Baz(s string) Foo
+//	}
+//
+//	var _ FooAPI = Foo{}
+func InterfaceDecls(j *Type, funcs []*Func) []ast.Decl {
+	methods := &ast.FieldList{Opening: token.Pos(1), Closing: token.Pos(1)}
+	for _, p := range j.Parents {
+		methods.List = append(methods.List, &ast.Field{Type: &ast.Ident{Name: InterfaceName(p)}})
+	}
+	for _, f := range funcs {
+		if id, ok := f.ReceiverType.(*ast.Ident); ok && id.Name == j.Name {
+			methods.List = append(methods.List, &ast.Field{
+				Names: []*ast.Ident{&ast.Ident{Name: f.Name}},
+				Type:  FuncDecl(f).Type,
+			})
+		}
+	}
+	if len(methods.List) > 0 {
+		methods.Opening, methods.Closing = token.NoPos, token.NoPos
+	}
+	name := InterfaceName(j)
+	return []ast.Decl{
+		&ast.GenDecl{
+			Tok: token.TYPE,
+			Doc: Doc(fmt.Sprintf("%s is the interface of the methods of %s.", name, j.Name)),
+			Specs: []ast.Spec{
+				&ast.TypeSpec{Name: &ast.Ident{Name: name}, Type: &ast.InterfaceType{Methods: methods}},
+			},
+		},
+		&ast.GenDecl{
+			Tok: token.VAR,
+			Specs: []ast.Spec{
+				&ast.ValueSpec{
+					Names:  []*ast.Ident{&ast.Ident{Name: "_"}},
+					Type:   &ast.Ident{Name: name},
+					Values: []ast.Expr{&ast.CompositeLit{Type: &ast.Ident{Name: j.Name}}},
+				},
+			},
+		},
+	}
+}
+
+//Global returns the expression of a js global object, from its 'path' ("foo.Bar" gives js.Global.Get("foo").Get("Bar"))
+func Global(path string) ast.Expr {
+	var x ast.Expr = &ast.SelectorExpr{
+		X:   &ast.Ident{Name: "js"},
+		Sel: &ast.Ident{Name: "Global"},
+	}
+	for _, name := range strings.Split(path, ".") {
+		x = &ast.CallExpr{
+			Fun: &ast.SelectorExpr{
+				X:   x,
+				Sel: &ast.Ident{Name: "Get"},
+			},
+			Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", name)}},
+		}
+	}
+	return x
+}
+
+//ConstructorDecl generates the function that creates a new js object of the type, with the js "new" operator.
+//
+//	func NewFoo(a int) Foo {
+//		return newFoo(js.Global.Get("Foo").New(a))
+//	}
+func ConstructorDecl(j *Type) *ast.FuncDecl {
+	c := j.Constructor
+	params := c.Params
+	if params == nil {
+		params = &ast.FieldList{}
+	}
+
+	class := Global(c.JS)
+
+	args := make([]ast.Expr, 0, params.NumFields())
+	ellipsis := token.NoPos
+	for _, p := range params.List {
+		for _, n := range p.Names {
+			args = append(args, n)
+		}
+		if _, ok := p.Type.(*ast.Ellipsis); ok {
+			ellipsis = token.Pos(1)
+		}
+	}
+
+	return &ast.FuncDecl{
+		Doc:  Doc(c.Description),
+		Name: &ast.Ident{Name: ConstructorName(j)},
+		Type: &ast.FuncType{
+			Params: params,
+			Results: &ast.FieldList{
+				List: []*ast.Field{
+					&ast.Field{Type: &ast.Ident{Name: j.Name}},
+				},
+			},
+		},
+		Body: &ast.BlockStmt{
+			List: []ast.Stmt{
+				&ast.ReturnStmt{
+					Results: []ast.Expr{
+						&ast.CallExpr{
+							Fun: &ast.Ident{Name: CtorName(j)},
+							Args: []ast.Expr{
+								&ast.CallExpr{
+									Fun: &ast.SelectorExpr{
+										X:   class,
+										Sel: &ast.Ident{Name: "New"},
+									},
+									Args:     args,
+									Ellipsis: ellipsis,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+//Ctor generates the function that wraps a *js.Object into the type.
+//
+// parents are initialized with their own ctor, on the same *js.Object: every embedded Object
+// is the same js object, the one of the type is promoted (it is the shallowest), and the
+// methods inherited from the parents call the same js object.
 func Ctor(j *Type) *ast.FuncDecl {
 	v := "j" // the name of the argument name
+
+	elts := []ast.Expr{
+		&ast.KeyValueExpr{
+			Key:   &ast.Ident{Name: "Object"},
+			Value: &ast.Ident{Name: v},
+		},
+	}
+	for _, p := range j.Parents {
+		elts = append(elts, &ast.KeyValueExpr{
+			Key: &ast.Ident{Name: p.Name},
+			Value: &ast.CallExpr{
+				Fun:  &ast.Ident{Name: CtorName(p)},
+				Args: []ast.Expr{&ast.Ident{Name: v}},
+			},
+		})
+	}
+
+	var doc *ast.CommentGroup
+	if j.Exported {
+		doc = Doc(fmt.Sprintf("%s returns the %s wrapping an existing javascript object.", CtorName(j), j.Name))
+	}
+
 	return &ast.FuncDecl{
-		Name: &ast.Ident{Name: "new" + j.Name},
+		Doc:  doc,
+		Name: &ast.Ident{Name: CtorName(j)},
 		Type: &ast.FuncType{
 			Params: &ast.FieldList{
 				List: []*ast.Field{
@@ -109,12 +392,7 @@ func Ctor(j *Type) *ast.FuncDecl {
 					Results: []ast.Expr{
 						&ast.CompositeLit{
 							Type: &ast.Ident{Name: j.Name},
-							Elts: []ast.Expr{
-								&ast.KeyValueExpr{
-									Key:   &ast.Ident{Name: "Object"},
-									Value: &ast.Ident{Name: v},
-								},
-							},
+							Elts: elts,
 						},
 					},
 				},
@@ -155,11 +433,7 @@ func FuncDecl(f *Func) (fd *ast.FuncDecl) {
 		}
 	}
 
-	if f.Description != "" {
-		fd.Doc = &ast.CommentGroup{
-			List: []*ast.Comment{&ast.Comment{Text: "// " + f.Description}},
-		}
-	}
+	fd.Doc = Doc(f.Doc())
 	// 	returnStmt,
 	// }},
 
@@ -172,23 +446,28 @@ func FuncDecl(f *Func) (fd *ast.FuncDecl) {
 
 	for i, p := range f.Params.List {
 		args[i+1] = p.Names[0]
+		if i < len(f.ConvertArgs) && f.ConvertArgs[i] != nil {
+			args[i+1] = f.ConvertArgs[i](p.Names[0])
+		}
 	}
 
 	ellipsis := token.NoPos //default is no ellipsis
 
-	if f.Params.NumFields() > 0 {
-
-		if _, ok := f.Params.List[f.Params.NumFields()-1].Type.(*ast.Ellipsis); ok {
+	if last := len(f.Params.List) - 1; last >= 0 {
+		// a converted variadic param is passed as a single (array) argument
+		_, ok := f.Params.List[last].Type.(*ast.Ellipsis)
+		if ok && (last >= len(f.ConvertArgs) || f.ConvertArgs[last] == nil) {
 			ellipsis = token.Pos(1) //it is an ellipsis create a position for the ellipsis
 		}
 	}
-	selector := f.ReceiverName
+	var selector ast.Expr = &ast.Ident{Name: f.ReceiverName}
+	if f.lazyReceiver {
+		selector = &ast.CallExpr{Fun: selector}
+	}
 
 	call := &ast.CallExpr{
 		Fun: &ast.SelectorExpr{
-			X: &ast.Ident{
-				Name: selector,
-			},
+			X: selector,
 			Sel: &ast.Ident{
 				Name: "Call",
 			},
@@ -197,10 +476,21 @@ func FuncDecl(f *Func) (fd *ast.FuncDecl) {
 		Ellipsis: ellipsis,
 	}
 
-	//two cases: either we have to return something (and cast the call result) or not
-	if f.ResultType == nil {
+	//three cases: either we have to return something (and cast the call result), or the receiver itself, or nothing
+	switch {
+	case f.ResultType == nil:
 		fd.Body.List[0] = &ast.ExprStmt{X: call}
-	} else {
+
+	case f.ReturnsReceiver && f.ReceiverType != nil:
+		// js returns 'this', there is no need to wrap it again
+		fd.Body.List = []ast.Stmt{
+			&ast.ExprStmt{X: call},
+			&ast.ReturnStmt{
+				Results: []ast.Expr{&ast.Ident{Name: f.ReceiverName}},
+			},
+		}
+
+	default:
 		//I just need to build a reutrn statement and a conversion
 		fd.Body.List[0] = &ast.ReturnStmt{
 			Results: []ast.Expr{f.Convert(call)},
@@ -210,6 +500,14 @@ func FuncDecl(f *Func) (fd *ast.FuncDecl) {
 
 }
 
+//Doc returns the description of the func, followed by its "Deprecated:" paragraph if any
+func (f *Func) Doc() string {
+	if f.Deprecated == "" {
+		return f.Description
+	}
+	return strings.TrimSpace(f.Description+"\n\nDeprecated: "+f.Deprecated) + "."
+}
+
 func mConverter(e ast.Expr, method string) ast.Expr {
 	return &ast.CallExpr{
 		Fun: &ast.SelectorExpr{
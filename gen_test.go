@@ -1,6 +1,7 @@
 package apigen
 
 import (
+	"fmt"
 	"go/ast"
 	"go/printer"
 	"go/token"
@@ -133,8 +134,9 @@ func ExampleFile() {
 		Funcs: []*Func{
 			&Func{
 				//Description: "Foo function",
-				Name: "Fooer",
-				JS:   "foo",
+				ReceiverName: "JQ",
+				Name:         "Fooer",
+				JS:           "foo",
 				Params: &ast.FieldList{
 					List: []*ast.Field{
 						&ast.Field{
@@ -151,23 +153,170 @@ func ExampleFile() {
 	printer.Fprint(os.Stdout, token.NewFileSet(), file)
 	//Output:
 	// package jquery
-
+	//
 	// import (
 	// 	"github.com/gopherjs/gopherjs/js"
 	// 	"github.com/gopherjs/gopherjs/jquery"
 	// )
-
+	//
 	// type Foo struct {
 	// 	*js.Object
 	// 	Bar	*js.Object	`js:"bar"`
 	// 	Baz	bool		`js:"baz"`
 	// }
-
+	//
 	// func newFoo(j *js.Object) Foo {
 	// 	return Foo{Object: j}
 	// }
-	// func Fooer(b bool) (x bool) {
+	// func Fooer(b bool) bool {
 	// 	return JQ.Call("foo", b).Bool()
 	// }
+}
+
+func ExampleDoc() {
+	doc := Doc("Foo does a lot of things, so many things that its description does not fit on a single line of comment.\n\n\tfoo.Bar()\n")
+	for _, c := range doc.List {
+		fmt.Println(c.Text)
+	}
+	//Output:
+	// // Foo does a lot of things, so many things that its description does not fit on
+	// // a single line of comment.
+	// //
+	// //	foo.Bar()
+}
+
+func ExampleSource() {
+	api := &Api{
+		Name:    "jquery",
+		Imports: []string{"github.com/gopherjs/gopherjs/js"},
+		Types: []*Type{
+			&Type{
+				Description: "Foo is a foo.",
+				Name:        "Foo",
+				Properties: []*Property{
+					&Property{
+						Description: "Bar is the bar.",
+						Name:        "Bar",
+						Type:        &ast.Ident{Name: "bool"},
+						JS:          "bar",
+					},
+				},
+			}},
+	}
+
+	src, _ := Source(api)
+	fmt.Print(string(src))
+	//Output:
+	// // Code generated by apigen. DO NOT EDIT.
+	//
+	// package jquery
+	//
+	// import (
+	// 	"github.com/gopherjs/gopherjs/js"
+	// )
+	//
+	// // Foo is a foo.
+	// type Foo struct {
+	// 	*js.Object
+	// 	// Bar is the bar.
+	// 	Bar bool `js:"bar"`
+	// }
+	//
+	// func newFoo(j *js.Object) Foo {
+	// 	return Foo{Object: j}
+	// }
+}
+
+func ExampleCtor_parents() {
+	deferred := &Type{Name: "Deferred"}
+	ty := &Type{
+		Name:    "JqXHR",
+		Parents: []*Type{deferred},
+	}
+	printer.Fprint(os.Stdout, token.NewFileSet(), TypeDecl(ty))
+	fmt.Println()
+	printer.Fprint(os.Stdout, token.NewFileSet(), Ctor(ty))
+	//Output:
+	// type JqXHR struct {
+	// 	*js.Object
+	// 	Deferred
+	// }
+	// func newJqXHR(j *js.Object) JqXHR {
+	// 	return JqXHR{Object: j, Deferred: newDeferred(j)}
+	// }
+}
+
+func ExampleConstDecl() {
+	consts := []*Const{
+		&Const{Name: "EventClick", Value: &ast.BasicLit{Kind: token.STRING, Value: `"click"`}},
+		&Const{Name: "EventFocus", Value: &ast.BasicLit{Kind: token.STRING, Value: `"focus"`}},
+	}
+	printer.Fprint(os.Stdout, token.NewFileSet(), ConstDecl(consts))
+	//Output:
+	// const (
+	// 	EventClick	= "click"
+	// 	EventFocus	= "focus"
+	// )
+}
+
+func ExampleVarDecl() {
+	vars := []*Var{
+		&Var{Name: "JQ", Value: Global("jQuery")},
+		&Var{Name: "Event", Value: Global("jQuery.Event")},
+	}
+	printer.Fprint(os.Stdout, token.NewFileSet(), VarDecl(vars))
+	//Output:
+	// var (
+	// 	JQ	= js.Global.Get("jQuery")
+	// 	Event	= js.Global.Get("jQuery").Get("Event")
+	// )
+}
 
+func ExampleLazyVarDecl() {
+	v := &Var{Name: "JQ", Value: Global("jQuery"), Lazy: true}
+	printer.Fprint(os.Stdout, token.NewFileSet(), LazyVarDecl(v))
+	//Output:
+	// func JQ() *js.Object {
+	// 	return js.Global.Get("jQuery")
+	// }
+}
+
+func ExampleConstructorDecl() {
+	ty := &Type{
+		Name:     "Event",
+		Exported: true,
+		Constructor: &Constructor{
+			JS: "jQuery.Event",
+			Params: &ast.FieldList{
+				List: []*ast.Field{
+					&ast.Field{
+						Names: []*ast.Ident{&ast.Ident{Name: "src"}},
+						Type:  &ast.Ident{Name: "string"},
+					},
+				}},
+		},
+	}
+	printer.Fprint(os.Stdout, token.NewFileSet(), ConstructorDecl(ty))
+	//Output:
+	// func NewEvent(src string) Event {
+	// 	return WrapEvent(js.Global.Get("jQuery").Get("Event").New(src))
+	// }
+}
+
+func ExampleFuncDecl_returnsReceiver() {
+	f := &Func{
+		Name:            "AddClass",
+		JS:              "addClass",
+		ReceiverName:    "x",
+		ReceiverType:    &ast.Ident{Name: "JQuery"},
+		Params:          &ast.FieldList{},
+		ResultType:      &ast.Ident{Name: "JQuery"},
+		ReturnsReceiver: true,
+	}
+	printer.Fprint(os.Stdout, token.NewFileSet(), FuncDecl(f))
+	//Output:
+	// func (x JQuery) AddClass() JQuery {
+	// 	x.Call("addClass")
+	// 	return x
+	// }
 }
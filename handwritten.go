@@ -0,0 +1,162 @@
+package apigen
+
+import (
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//Decls is the set of top level declarations of a package.
+//
+// types, funcs, vars and consts are stored by name, methods by "Receiver.Method"
+type Decls map[string]bool
+
+//ParseDecls parses the hand written go files of package 'pkg' in 'dir', and collects their declarations.
+//
+// Only the files built in the context 'ctxt' are parsed: their build constraints, and the GOOS and
+// GOARCH of their name, are evaluated as go build would (see Target.BuildContext).
+// Generated files (with the standard "Code generated ... DO NOT EDIT." header), test files, and
+// the files named in 'exclude' are ignored. The generated files must be excluded by name too, as
+// the ones written by older generators have no header. A missing directory is not an error: it
+// has no declaration.
+func ParseDecls(ctxt *build.Context, dir, pkg string, exclude ...string) (Decls, error) {
+	decls := make(Decls)
+	excluded := make(map[string]bool)
+	for _, name := range exclude {
+		excluded[filepath.Base(name)] = true
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return decls, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasSuffix(name, "_test.go") || excluded[name] {
+			continue
+		}
+		if match, err := ctxt.MatchFile(dir, name); err != nil || !match {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if file.Name.Name != pkg || ast.IsGenerated(file) {
+			continue
+		}
+		decls.add(file)
+	}
+	return decls, nil
+}
+
+//add collects the top level declarations of 'file'
+func (d Decls) add(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil || len(decl.Recv.List) == 0 {
+				d[decl.Name.Name] = true
+			} else {
+				d[receiverName(decl.Recv.List[0].Type)+"."+decl.Name.Name] = true
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					d[spec.Name.Name] = true
+				case *ast.ValueSpec:
+					for _, n := range spec.Names {
+						d[n.Name] = true
+					}
+				}
+			}
+		}
+	}
+}
+
+//receiverName returns the type name of a receiver type expression ( "Foo" for both Foo, and *Foo)
+func receiverName(e ast.Expr) string {
+	switch e := e.(type) {
+	case *ast.StarExpr:
+		return receiverName(e.X)
+	case *ast.ParenExpr:
+		return receiverName(e.X)
+	case *ast.IndexExpr: // generic receiver
+		return receiverName(e.X)
+	case *ast.IndexListExpr:
+		return receiverName(e.X)
+	case *ast.Ident:
+		return e.Name
+	default:
+		return ""
+	}
+}
+
+//Suppress removes from 'api' the types, vars and funcs already declared in 'decls'.
+//
+// A suppressed type is suppressed with its ctor, a hand written ctor only suppresses the ctor.
+// It returns the names of the suppressed members ("Foo", "Foo.Bar", "newFoo" for a ctor, or "NewFoo" for a constructor)
+//
+// Only the lists of the api are replaced, their members are left untouched (the changed types are copies):
+// suppress the declarations of several packages from shallow copies of the same api.
+func (api *Api) Suppress(decls Decls) (suppressed []string) {
+	var types []*Type
+	for _, ty := range api.Types {
+		if decls[ty.Name] {
+			suppressed = append(suppressed, ty.Name)
+			continue
+		}
+		handCtor := decls[CtorName(ty)]
+		handConstructor := ty.Constructor != nil && decls[ConstructorName(ty)]
+		if handCtor || handConstructor {
+			c := *ty
+			ty = &c
+		}
+		if handCtor {
+			suppressed = append(suppressed, CtorName(ty))
+			ty.handCtor = true
+		}
+		if handConstructor {
+			suppressed = append(suppressed, ConstructorName(ty))
+			ty.Constructor = nil
+		}
+		types = append(types, ty)
+	}
+	api.Types = types
+
+	var vars []*Var
+	for _, v := range api.Vars {
+		if decls[v.Name] {
+			suppressed = append(suppressed, v.Name)
+			continue
+		}
+		vars = append(vars, v)
+	}
+	api.Vars = vars
+
+	var funcs []*Func
+	for _, f := range api.Funcs {
+		name := f.Name
+		if f.ReceiverType != nil {
+			name = receiverName(f.ReceiverType) + "." + f.Name
+		}
+		if decls[name] {
+			suppressed = append(suppressed, name)
+			continue
+		}
+		funcs = append(funcs, f)
+	}
+	api.Funcs = funcs
+	return
+}
@@ -0,0 +1,25 @@
+package apigen
+
+import (
+	"fmt"
+	"go/ast"
+)
+
+func ExampleApi_Suppress() {
+	api := &Api{
+		Name:  "jquery",
+		Types: []*Type{&Type{Name: "Foo"}, &Type{Name: "Bar"}},
+		Funcs: []*Func{
+			&Func{Name: "Baz", ReceiverType: &ast.Ident{Name: "Foo"}},
+			&Func{Name: "Qux", ReceiverType: &ast.Ident{Name: "Foo"}},
+		},
+	}
+	// as if parsed from: type Bar struct{}; func (f *Foo) Baz() {}
+	decls := Decls{"Bar": true, "Foo.Baz": true}
+
+	fmt.Println(api.Suppress(decls))
+	fmt.Println(len(api.Types), len(api.Funcs))
+	//Output:
+	// [Bar Foo.Baz]
+	// 1 1
+}
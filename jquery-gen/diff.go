@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+//context is the number of unchanged lines around the changes of a unified diff
+const context = 3
+
+//edit is a line of an edit script: kept (' '), deleted ('-') or inserted ('+')
+type edit struct {
+	op   byte
+	line string
+}
+
+//unifiedDiff returns the unified diff from 'a' to 'b' ("" if they are equal)
+func unifiedDiff(aName, bName string, a, b []byte) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+	edits := lineDiff(lines(a), lines(b))
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", aName, bName)
+
+	// a and b line numbers before every edit
+	aLine, bLine := make([]int, len(edits)+1), make([]int, len(edits)+1)
+	for i, e := range edits {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if e.op != '+' {
+			aLine[i+1]++
+		}
+		if e.op != '-' {
+			bLine[i+1]++
+		}
+	}
+
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+			continue
+		}
+		// a hunk, from the context before the change, to the context after the last close enough change
+		start, end := i-context, i
+		if start < 0 {
+			start = 0
+		}
+		for j := i; j < len(edits) && j < end+2*context+1; j++ {
+			if edits[j].op != ' ' {
+				end = j
+			}
+		}
+		end += context + 1
+		if end > len(edits) {
+			end = len(edits)
+		}
+
+		aCount, bCount := aLine[end]-aLine[start], bLine[end]-bLine[start]
+		aStart, bStart := aLine[start], bLine[start]
+		if aCount > 0 {
+			aStart++
+		}
+		if bCount > 0 {
+			bStart++
+		}
+		fmt.Fprintf(&buf, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+		for _, e := range edits[start:end] {
+			fmt.Fprintf(&buf, "%c%s\n", e.op, e.line)
+		}
+		i = end
+	}
+	return buf.String()
+}
+
+//lines splits 'content' into lines, without their line feed
+func lines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+//lineDiff returns the shortest edit script from 'a' to 'b' (Myers' algorithm)
+func lineDiff(a, b []string) []edit {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 { // a new, or a removed file: no need to search
+		edits := make([]edit, 0, n+m)
+		for _, l := range a {
+			edits = append(edits, edit{'-', l})
+		}
+		for _, l := range b {
+			edits = append(edits, edit{'+', l})
+		}
+		return edits
+	}
+	offset := n + m + 1 // v is indexed by the diagonal k, from -(n+m) to n+m
+	v := make([]int, 2*offset+1)
+	var trace [][]int // the diagonals -d to d of v, before every step d: O(d²) and not O((n+m)²)
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // down: insert from b
+			} else {
+				x = v[offset+k-1] + 1 // right: delete from a
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+	return nil // unreachable, d = n+m always reaches the end
+}
+
+//backtrack builds the edit script from the trace of lineDiff
+func backtrack(trace [][]int, a, b []string) []edit {
+	var edits []edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d] // v[d+k] is the diagonal k
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[d+k-1] < v[d+k+1]) {
+			prevK = k + 1
+		}
+		prevX := 0
+		if d > 0 {
+			prevX = v[d+prevK]
+		}
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, edit{' ', a[x-1]})
+			x, y = x-1, y-1
+		}
+		if d == 0 {
+			break
+		}
+		if prevK == k+1 {
+			edits = append(edits, edit{'+', b[prevY]})
+		} else {
+			edits = append(edits, edit{'-', a[prevX]})
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
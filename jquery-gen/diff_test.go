@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func Example_unifiedDiff() {
+	a := []byte("package jquery\nfunc A() {}\nfunc B() {}\nfunc C() {}\nfunc D() {}\nfunc E() {}\nfunc F() {}\nfunc G() {}\nfunc H() {}\nfunc I() {}\n")
+	b := []byte("package jquery\nfunc A() {}\nfunc B2() {}\nfunc C() {}\nfunc D() {}\nfunc E() {}\nfunc F() {}\nfunc G() {}\nfunc H() {}\nfunc I() {}\nfunc J() {}\n")
+	fmt.Print(unifiedDiff("jquery.go", "generated", a, b))
+	fmt.Printf("%q\n", unifiedDiff("jquery.go", "generated", a, a))
+	//Output:
+	// --- jquery.go
+	// +++ generated
+	// @@ -1,6 +1,6 @@
+	//  package jquery
+	//  func A() {}
+	// -func B() {}
+	// +func B2() {}
+	//  func C() {}
+	//  func D() {}
+	//  func E() {}
+	// @@ -8,3 +8,4 @@
+	//  func G() {}
+	//  func H() {}
+	//  func I() {}
+	// +func J() {}
+	// ""
+}
+
+//TestLineDiffLarge diffs large files, against an empty one, and with a single change
+func TestLineDiffLarge(t *testing.T) {
+	a := make([]string, 6000)
+	for i := range a {
+		a[i] = fmt.Sprintf("line %d", i)
+	}
+	if edits := lineDiff(a, nil); len(edits) != len(a) || edits[0] != (edit{'-', "line 0"}) {
+		t.Errorf("lineDiff(a, nil) = %d edits, want %d deletions", len(edits), len(a))
+	}
+	if edits := lineDiff(nil, a); len(edits) != len(a) || edits[len(a)-1] != (edit{'+', "line 5999"}) {
+		t.Errorf("lineDiff(nil, a) = %d edits, want %d insertions", len(edits), len(a))
+	}
+
+	b := append([]string(nil), a...)
+	b[3000] = "changed"
+	var changes []edit
+	for _, e := range lineDiff(a, b) {
+		if e.op != ' ' {
+			changes = append(changes, e)
+		}
+	}
+	if want := []edit{{'-', "line 3000"}, {'+', "changed"}}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("lineDiff(a, b) changes = %v, want %v", changes, want)
+	}
+}
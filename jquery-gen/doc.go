@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ericaro/apigen"
+)
+
+//doc writes the reference of the binding, one page per type and an index
+//
+//	jquery-gen doc -i entries -o dir [-format md|html]
+func doc(args []string) error {
+	o := newOptions("doc")
+	dir := o.flags.String("o", "", "output directory of the pages")
+	format := o.flags.String("format", apigen.Markdown, "format of the pages, 'md' or 'html'")
+	if err := o.parse(args); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return usageError{errors.New("missing output directory (-o)")}
+	}
+	if *format != apigen.Markdown && *format != apigen.HTML {
+		return usageError{fmt.Errorf("unknown format %q", *format)}
+	}
+	outapi, rep, err := o.compile()
+	if err != nil {
+		return err
+	}
+	if err := o.writeReport(rep); err != nil {
+		return err
+	}
+
+	pages, err := apigen.Reference(outapi, *format)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		return err
+	}
+	for _, p := range pages {
+		if err := writeFileAtomic(filepath.Join(*dir, p.Name), p.Content); err != nil {
+			return fmt.Errorf("cannot write the reference: %v", err)
+		}
+	}
+	log.Printf("generated %d pages of %v to %v", len(pages), o.input, *dir)
+	return nil
+}
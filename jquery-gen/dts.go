@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ericaro/apigen"
+)
+
+//dts writes the typescript declarations of the jQuery api wrapped by the binding
+//
+//	jquery-gen dts -i entries [-o jquery.d.ts]
+func dts(args []string) error {
+	o := newOptions("dts")
+	file := o.flags.String("o", "", "output .d.ts file (default to stdout)")
+	if err := o.parse(args); err != nil {
+		return err
+	}
+	outapi, rep, err := o.compile()
+	if err != nil {
+		return err
+	}
+	if err := o.writeReport(rep); err != nil {
+		return err
+	}
+
+	src := apigen.DTS(outapi)
+	if *file == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	if err := writeFileAtomic(*file, src); err != nil {
+		return fmt.Errorf("cannot write the declarations: %v", err)
+	}
+	log.Printf("generated %v to %v", o.input, *file)
+	return nil
+}
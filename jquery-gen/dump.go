@@ -0,0 +1,23 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+)
+
+//dump prints the parsed entries as json, to debug the parser
+//
+//	jquery-gen dump -i entries
+func dump(args []string) error {
+	o := newOptions("dump")
+	if err := o.parse(args); err != nil {
+		return err
+	}
+	api, err := o.parseEntries()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(api)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ericaro/apigen"
+	"github.com/ericaro/apigen/apijquery"
+)
+
+//generate writes the binding
+//
+//	jquery-gen generate -i entries [-o dir] [-targets gopherjs,wasm] [-report text|json] [-verify]
+//
+// with -verify, nothing is written: the binding is compared to the existing one (like diff), for CI.
+func generate(args []string) error {
+	o := newOptions("generate")
+	o.outputFlags()
+	verify := o.flags.Bool("verify", false, "do not write anything, fail with a diff if the existing binding is not up to date")
+	if err := o.parse(args); err != nil {
+		return err
+	}
+	if *verify {
+		return o.verify()
+	}
+	bindings, err := o.bindings()
+	if err != nil {
+		return err
+	}
+
+	for _, b := range bindings {
+		if b.file == "" {
+			_, err = os.Stdout.Write(b.src)
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(b.file), 0755); err != nil {
+			return err
+		}
+		if err := writeFileAtomic(b.file, b.src); err != nil {
+			return fmt.Errorf("cannot write the binding: %v", err)
+		}
+		log.Printf("generated %v to %v", o.input, b.file)
+	}
+	return nil
+}
+
+//check parses and compiles the entries, and reports the errors
+//
+//	jquery-gen check -i entries [-report text|json]
+func check(args []string) error {
+	o := newOptions("check")
+	o.flags.StringVar(&o.report, "report", "", "print the compile report to stderr, as 'text' or 'json'")
+	if err := o.parse(args); err != nil {
+		return err
+	}
+	_, err := o.bindings()
+	return err
+}
+
+//diff prints the unified diff between the existing binding and the generated one
+//
+//	jquery-gen diff -i entries -o dir [-targets gopherjs,wasm]
+func diff(args []string) error {
+	o := newOptions("diff")
+	o.outputFlags()
+	if err := o.parse(args); err != nil {
+		return err
+	}
+	return o.verify()
+}
+
+//verify generates the bindings in memory, and prints their unified diff with the existing ones.
+//
+// it returns errChanged if they differ.
+func (o *options) verify() error {
+	if o.outputFile() == "" {
+		return usageError{fmt.Errorf("missing the binding to compare with (-o)")}
+	}
+	bindings, err := o.bindings()
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, b := range bindings {
+		existing, err := os.ReadFile(b.file)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if d := unifiedDiff(b.file, b.file+" (generated)", existing, b.src); d != "" {
+			fmt.Print(d)
+			changed = true
+		}
+	}
+	if changed {
+		return errChanged
+	}
+	return nil
+}
+
+//binding is a generated source file
+type binding struct {
+	file string // "" for stdout
+	src  []byte
+}
+
+//bindings generates the binding source, or one per target.
+//
+// the report is printed, and the declarations hand written in the output package are suppressed (see suppress).
+func (o *options) bindings() ([]binding, error) {
+	outapi, rep, err := o.compile()
+	if err != nil {
+		return nil, err
+	}
+	if err := o.writeReport(rep); err != nil {
+		return nil, err
+	}
+
+	if len(o.targets) == 0 {
+		api, err := o.suppress(outapi, apigen.GopherJS)
+		if err != nil {
+			return nil, err
+		}
+		src, err := apigen.Source(api)
+		if err != nil {
+			return nil, fmt.Errorf("cannot generate the binding: %v", err)
+		}
+		return []binding{{o.outputFile(), src}}, nil
+	}
+	var bindings []binding
+	for _, t := range o.targets {
+		api, err := o.suppress(outapi, t)
+		if err != nil {
+			return nil, err
+		}
+		src, err := apigen.TargetSource(api, t)
+		if err != nil {
+			return nil, fmt.Errorf("cannot generate the %v binding: %v", t.Name, err)
+		}
+		bindings = append(bindings, binding{o.targetFile(t), src})
+	}
+	return bindings, nil
+}
+
+//suppress returns a copy of 'api' without the declarations hand written in the output package,
+// for the target 't': only the files built by the target toolchain count.
+//
+// hand written code in the target package takes precedence over the generated one.
+// the generated files are excluded by name: older bindings have no "Code generated" header
+func (o *options) suppress(api *apigen.Api, t apigen.Target) (*apigen.Api, error) {
+	file := o.outputFile()
+	if file == "" {
+		return api, nil
+	}
+	generated := []string{file}
+	for _, t := range apigen.Targets {
+		generated = append(generated, o.targetFile(t))
+	}
+	ctxt := t.BuildContext()
+	decls, err := apigen.ParseDecls(&ctxt, filepath.Dir(file), api.Name, generated...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse hand written code: %v", err)
+	}
+	c := *api
+	for _, name := range c.Suppress(decls) {
+		log.Printf("suppressing %v from the %v binding: already declared by hand", name, t.Name)
+	}
+	return &c, nil
+}
+
+//writeReport prints the compile report in the -report format, or just its summary
+func (o *options) writeReport(rep *apijquery.Report) error {
+	switch o.report {
+	case "":
+		log.Printf("compiled: %v", rep.Summary())
+		return nil
+	case "text":
+		return rep.WriteText(os.Stderr)
+	case "json":
+		enc := json.NewEncoder(os.Stderr)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rep)
+	default:
+		return usageError{fmt.Errorf("unknown report format %q, expecting 'text' or 'json'", o.report)}
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//TestBindingsOldBinding regenerates over a binding written before the "Code generated" header:
+// it must not be taken for hand written code, and suppress the whole api.
+func TestBindingsOldBinding(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOPACKAGE", "")
+	t.Setenv("GOFILE", "")
+	for name, content := range map[string]string{
+		"jquery_gen.go":      "package jquery\n\ntype JQuery struct{}\n\nfunc (x JQuery) AddClass(className string) JQuery { return x }\n",
+		"jquery_gen_wasm.go": "package jquery\n\nfunc (x JQuery) FadeIn(i ...interface{}) JQuery { return x }\n",
+		"val.go":             "package jquery\n\nfunc (x JQuery) Val() string { return \"\" }\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	o := newOptions("generate")
+	o.outputFlags()
+	if err := o.parse([]string{"-i", filepath.Join("..", "apijquery", "testdata", "entries"), "-o", dir}); err != nil {
+		t.Fatal(err)
+	}
+	bindings, err := o.bindings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := bindings[0].src
+	for _, decl := range []string{"type JQuery struct", "func (x JQuery) AddClass(", "func (x JQuery) FadeIn("} {
+		if !bytes.Contains(src, []byte(decl)) {
+			t.Errorf("%q is missing from the binding", decl)
+		}
+	}
+	if bytes.Contains(src, []byte("func (x JQuery) Val(")) {
+		t.Errorf("the hand written Val is generated again")
+	}
+}
+
+func TestGenerateNewDir(t *testing.T) {
+	t.Setenv("GOPACKAGE", "")
+	t.Setenv("GOFILE", "")
+	dir := filepath.Join(t.TempDir(), "web", "jquery")
+	if err := generate([]string{"-i", filepath.Join("..", "apijquery", "testdata", "entries"), "-o", dir}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "jquery_gen.go")); err != nil {
+		t.Error(err)
+	}
+}
+
+//TestBindingsTargetDecls checks that the hand written code suppresses the generated one only in the
+// bindings of the targets it is built for
+func TestBindingsTargetDecls(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOPACKAGE", "")
+	t.Setenv("GOFILE", "")
+	for name, content := range map[string]string{
+		"delay_wasm.go": "package jquery\n\nfunc (x JQuery) Delay(duration interface{}) JQuery { return x }\n",
+		"val.go":        "//go:build !js\n\npackage jquery\n\nfunc (x JQuery) Val() string { return \"\" }\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	o := newOptions("generate")
+	o.outputFlags()
+	if err := o.parse([]string{"-i", filepath.Join("..", "apijquery", "testdata", "entries"), "-o", dir, "-targets", "gopherjs,wasm,fake"}); err != nil {
+		t.Fatal(err)
+	}
+	bindings, err := o.bindings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][2]bool{ // declares Delay, declares Val
+		"jquery_gen_gopherjs.go": {true, true},
+		"jquery_gen_wasm.go":     {false, true},
+		"jquery_gen_fake.go":     {true, false},
+	}
+	for _, b := range bindings {
+		got := [2]bool{bytes.Contains(b.src, []byte("func (x JQuery) Delay(")), bytes.Contains(b.src, []byte("func (x JQuery) Val("))}
+		if w := want[filepath.Base(b.file)]; got != w {
+			t.Errorf("%v declares Delay, Val: %v, want %v", filepath.Base(b.file), got, w)
+		}
+	}
+}
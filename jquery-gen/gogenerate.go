@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//inferPackage returns the name of the package to generate in 'dir'.
+//
+// it is the package of the go files already in 'dir', or, when 'dir' is the current directory,
+// $GOPACKAGE (set by go generate) or the package of $GOFILE, or "jquery".
+func inferPackage(dir string) string {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		if pkg := packageOf(file); pkg != "" {
+			return pkg
+		}
+	}
+	// go generate runs in the directory of $GOFILE, its package is the one of 'dir' only if it is that directory
+	if !isCurrentDir(dir) {
+		return "jquery"
+	}
+	if pkg := os.Getenv("GOPACKAGE"); pkg != "" {
+		return pkg
+	}
+	if file := os.Getenv("GOFILE"); file != "" {
+		if pkg := packageOf(file); pkg != "" {
+			return pkg
+		}
+	}
+	return "jquery"
+}
+
+//isCurrentDir tells whether 'dir' is the current directory
+func isCurrentDir(dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	wd, err := os.Getwd()
+	return err == nil && filepath.Clean(wd) == abs
+}
+
+//packageOf returns the package name of a go file ("" if it cannot be parsed)
+func packageOf(file string) string {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return ""
+	}
+	return f.Name.Name
+}
+
+//moduleRoot returns the directory of the nearest go.mod, from the current directory up
+func moduleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("no go.mod found")
+		}
+		dir = parent
+	}
+}
+
+//resolveInput returns the path of the input entries.
+//
+// a relative path is resolved from the current directory, then from the module root, so that
+// a //go:generate directive does not depend on the depth of its package in the module.
+func resolveInput(input string) string {
+	if filepath.IsAbs(input) {
+		return input
+	}
+	if _, err := os.Stat(input); err == nil {
+		return input
+	}
+	root, err := moduleRoot()
+	if err != nil {
+		return input // the parser will report the missing input
+	}
+	return filepath.Join(root, input)
+}
+
+//writeFileAtomic writes 'data' to a temporary file in the same directory, and renames it to 'name'.
+//
+// an interrupted write never leaves a truncated file.
+func writeFileAtomic(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
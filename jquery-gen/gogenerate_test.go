@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInferPackage(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOPACKAGE", "")
+	t.Setenv("GOFILE", "")
+	if got := inferPackage(dir); got != "jquery" {
+		t.Errorf("empty dir: got %q want jquery", got)
+	}
+
+	for name, content := range map[string]string{"a_test.go": "package jq_test\n", "a.go": "// Package jq\npackage jq\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := inferPackage(dir); got != "jq" {
+		t.Errorf("existing files: got %q want jq", got)
+	}
+
+	// the files already in the output directory win over go generate
+	t.Setenv("GOPACKAGE", "dom")
+	if got := inferPackage(dir); got != "jq" {
+		t.Errorf("existing files with go generate: got %q want jq", got)
+	}
+
+	// $GOPACKAGE is the package of the current directory only
+	empty := t.TempDir()
+	if got := inferPackage(empty); got != "jquery" {
+		t.Errorf("go generate, other dir: got %q want jquery", got)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(empty); err != nil {
+		t.Fatal(err)
+	}
+	if got := inferPackage("."); got != "dom" {
+		t.Errorf("go generate: got %q want dom", got)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "jquery_gen.go")
+	for _, content := range []string{"package jquery\n", "package jquery\n\nvar JQ = 1\n"} {
+		if err := writeFileAtomic(name, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(name)
+		if err != nil || string(got) != content {
+			t.Fatalf("got %q, %v want %q", got, err, content)
+		}
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 1 {
+		t.Errorf("temporary files are left: %v", files)
+	}
+}
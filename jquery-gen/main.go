@@ -1,63 +1,232 @@
 // jquery-gen is a tool to generate gopherjs jquery's binding from api.jquery.com
+//
+// Usage:
+//
+//	jquery-gen [command] [flags]
+//
+// The commands are:
+//
+//	generate  generate the binding (the default command)
+//	check     parse and compile the entries, without writing anything
+//	diff      print the changes the generation would make to the existing binding
+//	dump      print the parsed entries as json
+//	dts       print the typescript declarations of the jQuery api wrapped by the binding
+//	doc       write the Markdown or HTML reference of the binding
+//	stats     print the percentage of funcs with typed params, per type and per category
+//
+// Run "jquery-gen [command] -h" for the flags of a command.
+//
+// Messages are written to stderr. The exit code is 0 on success, 1 on errors (or when
+// diff, or generate -verify, finds changes), and 2 on usage errors.
+//
+// jquery-gen can be run by go generate, from the package of the binding:
+//
+//	//go:generate jquery-gen -i api.jquery.com/entries
+//
+// the package name is inferred, the input is resolved from the module root, and the binding is
+// written next to the file of the directive (atomically: a temporary file is renamed).
+//
+// With -targets gopherjs,wasm the binding is generated once per target, in jquery_gen_gopherjs.go
+// and jquery_gen_wasm.go, guarded by build constraints: the package builds with both toolchains.
+// The members hand written in the package are not generated, in the bindings of the targets that
+// build the files declaring them.
+// The fake target builds with the regular go toolchain, against the in-memory js package of
+// github.com/ericaro/apigen/jsfake, so that the code using the binding can be unit tested:
+// FakeJQuery returns a JQuery recording its calls, that implements JQueryAPI: the fake binding always
+// declares the interfaces, generate the other bindings with -interfaces to use them in the package.
+//
+// In CI, "jquery-gen -i entries -o dir -verify" fails when the binding has been edited by
+// hand, or has not been generated again after an update of the entries.
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"io"
+	"log"
 	"os"
-
-	"go/format"
-	"go/token"
+	"path/filepath"
+	"strings"
 
 	"github.com/ericaro/apigen"
 	"github.com/ericaro/apigen/apijquery"
 )
 
-var (
-	output = flag.String("o", "", "output directory (default to os.Stdout)")
-	input  = flag.String("i", "", "input directory where are the entries.xml ")
-)
+//command is a jquery-gen sub command
+type command struct {
+	name  string
+	short string                    // one line description
+	run   func(args []string) error // returns a usageError on invalid args
+}
+
+var commands = []command{
+	{"generate", "generate the binding (the default command)", generate},
+	{"check", "parse and compile the entries, without writing anything", check},
+	{"diff", "print the changes the generation would make to the existing binding", diff},
+	{"dump", "print the parsed entries as json", dump},
+	{"dts", "print the typescript declarations of the jQuery api wrapped by the binding", dts},
+	{"doc", "write the Markdown or HTML reference of the binding", doc},
+	{"stats", "print the percentage of funcs with typed params, per type and per category", stats},
+}
+
+//usageError is an error in the command line
+type usageError struct{ error }
+
+//errChanged is returned when the existing binding differs from the generated one
+var errChanged = errors.New("the binding is not up to date")
 
 func main() {
-	flag.Parse()
-
-	// create a writer either file (-o option) or stdout
-	var target io.Writer
-	if *output == "" {
-		target = os.Stdout
-	} else {
-		file, err := os.Create(*output)
-		if err != nil {
-			panic(fmt.Errorf("cannot write to %v: %v", output, err))
+	log.SetFlags(0)
+	log.SetPrefix("jquery-gen: ")
+
+	name, args := "generate", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		err := c.run(args)
+		switch err.(type) {
+		case nil:
+			return
+		case usageError:
+			if err.(usageError).error != flag.ErrHelp {
+				log.Print(err)
+			}
+			os.Exit(2)
+		default:
+			log.Print(err)
+			os.Exit(1)
 		}
-		defer file.Close()
-		target = file
 	}
+	log.Printf("unknown command %q", name)
+	usage()
+	os.Exit(2)
+}
 
-	// parse each entry as described in th
-	api, err := apijquery.Parse(*input)
-	if err != nil {
-		fmt.Printf("Error parsing xml entries: %v\n", err)
-		os.Exit(-1)
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: jquery-gen [command] [flags]\n\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-9s %s\n", c.name, c.short)
 	}
+}
 
-	c := apijquery.Compiler{}
-	outapi, err := c.Compile(api)
-	if err != nil {
-		fmt.Printf("Compilation error: %v\n", err)
-		os.Exit(-1)
+//options are the flags shared by the commands
+type options struct {
+	flags    *flag.FlagSet
+	input    string
+	output   string
+	report   string
+	targets  []apigen.Target // one binding per target, none for a single binding without build constraint
+	compiler apijquery.Compiler
+}
+
+//newOptions declares the flags of the command 'name'
+func newOptions(name string) *options {
+	o := &options{flags: flag.NewFlagSet(name, flag.ContinueOnError)}
+	o.flags.StringVar(&o.input, "i", "", "input directory where are the entries.xml, or a .zip/.tar.gz archive of it (relative to the current directory, or to the module root)")
+	o.flags.StringVar(&o.compiler.Package, "pkg", "", "name of the generated package (default to the package of the output directory, or $GOPACKAGE when it is the current directory, or jquery)")
+	o.flags.StringVar(&o.compiler.Import, "import", apijquery.JSImport, "import path of the gopherjs js package (or of a compatible one, the wasm ones require -targets wasm)")
+	o.flags.StringVar(&o.compiler.Global, "global", "jQuery", "js name of the jQuery global object")
+	o.flags.BoolVar(&o.compiler.Exported, "export", false, "export the wrappers of the generated types (WrapFoo instead of newFoo)")
+	o.flags.BoolVar(&o.compiler.Interfaces, "interfaces", false, "declare the interface of the methods of each generated type (JQueryAPI)")
+	o.flags.BoolVar(&o.compiler.Deprecated, "deprecated", false, "keep the deprecated entries that are not removed yet, marked as deprecated")
+	return o
+}
+
+//outputFlags declares the flags of the commands writing the binding
+func (o *options) outputFlags() {
+	o.flags.StringVar(&o.output, "o", "", "output directory, or .go file (default to stdout, or to the current directory when run by go generate)")
+	o.flags.StringVar(&o.report, "report", "", "print the compile report to stderr, as 'text' or 'json'")
+	o.flags.Func("targets", "comma separated targets (gopherjs,wasm,fake): generate one binding per target, guarded by its build constraint", func(names string) error {
+		o.targets = nil
+		for _, name := range strings.Split(names, ",") {
+			t, ok := apigen.LookupTarget(strings.TrimSpace(name))
+			if !ok {
+				return fmt.Errorf("unknown target %q", name)
+			}
+			o.targets = append(o.targets, t)
+		}
+		return nil
+	})
+}
+
+//parse parses the command line
+func (o *options) parse(args []string) error {
+	if err := o.flags.Parse(args); err != nil {
+		return usageError{err}
 	}
+	if o.input == "" {
+		return usageError{errors.New("missing input entries (-i)")}
+	}
+	if o.flags.NArg() > 0 {
+		return usageError{fmt.Errorf("unexpected arguments %v", o.flags.Args())}
+	}
+
+	// go generate runs in the directory of the package, where the binding belongs
+	if o.output == "" && o.flags.Lookup("o") != nil && os.Getenv("GOFILE") != "" {
+		o.output = "."
+	}
+	if len(o.targets) > 0 && o.output == "" {
+		return usageError{errors.New("-targets requires an output (-o)")}
+	}
+	o.input = resolveInput(o.input)
+	if o.compiler.Package == "" {
+		o.compiler.Package = inferPackage(o.outputDir())
+	}
+	return nil
+}
+
+//outputFile returns the path of the generated file ("" for stdout)
+func (o *options) outputFile() string {
+	if o.output == "" || strings.HasSuffix(o.output, ".go") {
+		return o.output
+	}
+	return filepath.Join(o.output, o.compiler.Package+"_gen.go")
+}
 
-	err = format.Node(target, token.NewFileSet(), apigen.File(outapi))
+//targetFile returns the path of the generated file for the target 't' (jquery_gen_wasm.go)
+func (o *options) targetFile(t apigen.Target) string {
+	return strings.TrimSuffix(o.outputFile(), ".go") + "_" + t.Name + ".go"
+}
+
+//outputDir returns the directory of the generated file
+func (o *options) outputDir() string {
+	switch {
+	case o.output == "":
+		return "."
+	case strings.HasSuffix(o.output, ".go"):
+		return filepath.Dir(o.output)
+	default:
+		return o.output
+	}
+}
+
+//parseEntries parses the input entries (a directory or an archive)
+func (o *options) parseEntries() (*apijquery.Api, error) {
+	parse := apijquery.Parse
+	if apijquery.IsArchive(o.input) {
+		parse = apijquery.ParseArchive
+	}
+	api, err := parse(o.input)
 	if err != nil {
-		fmt.Printf("ast to .go error: %v\n", err)
-		os.Exit(-1)
+		return nil, fmt.Errorf("cannot parse the xml entries: %v", err)
 	}
+	return api, nil
+}
 
-	if *output == "" {
-		fmt.Printf("generated %s/*.xml to stdout\n", *input)
-	} else {
-		fmt.Printf("generated %s/*.xml to %s\n", *input, *output)
+//compile parses and compiles the input entries
+func (o *options) compile() (*apigen.Api, *apijquery.Report, error) {
+	api, err := o.parseEntries()
+	if err != nil {
+		return nil, nil, err
+	}
+	outapi, rep, err := o.compiler.Compile(api)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot compile the entries: %v", err)
 	}
+	outapi.Generator = "jquery-gen"
+	return outapi, rep, nil
 }
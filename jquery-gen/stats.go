@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ericaro/apigen"
+)
+
+//stats prints the percentage of funcs with typed params, per type and per category.
+//
+//	jquery-gen stats -i entries [-previous stats.json] [-json]
+func stats(args []string) error {
+	o := newOptions("stats")
+	previous := o.flags.String("previous", "", "json stats of a previous run, to compare with")
+	asJSON := o.flags.Bool("json", false, "print the stats as json, to be used as -previous in a later run")
+	if err := o.parse(args); err != nil {
+		return err
+	}
+
+	outapi, _, err := o.compile()
+	if err != nil {
+		return err
+	}
+	s := apigen.ComputeStats(outapi)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(s)
+	}
+	var prev *apigen.Stats
+	if *previous != "" {
+		if prev, err = readStats(*previous); err != nil {
+			return fmt.Errorf("cannot read the previous stats: %v", err)
+		}
+	}
+	return s.WriteText(os.Stdout, prev)
+}
+
+//readStats reads json stats written by "jquery-gen stats -json"
+func readStats(name string) (*apigen.Stats, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s := new(apigen.Stats)
+	return s, json.NewDecoder(f).Decode(s)
+}
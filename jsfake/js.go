@@ -0,0 +1,352 @@
+// Package js is an in-memory fake of the gopherjs js package.
+//
+// The bindings generated for the apigen.Fake target import it instead of a js runtime: they build
+// with the regular go toolchain, and the code using them can be unit tested with go test.
+//
+// A fake object records the calls made on it, and returns the configured results:
+//
+//	x, o := jquery.FakeJQuery()
+//	o.Return("css", "red")
+//	x.CSS("color")   // "red"
+//	o.CallsTo("css") // [{css [color]}]
+//
+// The result of a method can also depend on the arguments, or on the call (see Answer). Calls
+// without a configured result return an empty object, the same one for every call of the method,
+// so that chained calls can be checked too.
+//
+// Function objects have a go implementation (Func), go funcs passed to a call are such objects
+// (see Of): the event handlers registered by the code under test are called by Fire.
+//
+// The static funcs of the bindings call the Global object, shared by the tests: the tests using it
+// call UseGlobal, the fakes of the FakeFoo funcs are not shared.
+//
+// The generated unions dispatch on the actual value of the fake objects (see TypeOf): a call
+// returning (*js.Object)(nil), or Undefined, is a nil union.
+package js
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+//Call is a call recorded by a fake object
+type Call struct {
+	Method string        // called method, InvokeMethod for Invoke, and NewMethod for New
+	Args   []interface{} // the go values passed to the call
+}
+
+const (
+	InvokeMethod = "()"  // the method of the calls recorded by Invoke
+	NewMethod    = "new" // the method of the calls recorded by New
+)
+
+//Object is a fake javascript object.
+//
+// Its methods can be called concurrently, read its Calls once the code under test is done (or use CallsTo).
+type Object struct {
+	Value   interface{}                   // go value of a primitive object (bool, string, number), nil otherwise
+	Func    func(args ...*Object) *Object // go implementation of a function object, called by Invoke and New
+	Calls   []Call                        // calls made on the object, in order
+	Results map[string]*Object            // results of the calls, by method
+	Props   map[string]*Object            // properties
+
+	mu sync.Mutex // guards Calls, Results and Props
+}
+
+//Global is the fake global object, shared by the tests: see UseGlobal
+var Global = new(Object)
+
+var globalMu sync.Mutex // held by the test using Global
+
+//UseGlobal gives the test 't' the use of Global, reset, until the end of the test: the tests using Global
+// run one at a time, even in parallel. Call it after t.Parallel, a paused test would keep Global.
+func UseGlobal(t interface{ Cleanup(func()) }) {
+	globalMu.Lock()
+	Global.Reset()
+	t.Cleanup(func() {
+		Global.Reset()
+		globalMu.Unlock()
+	})
+}
+
+//Undefined is the fake undefined value, and null is a nil *Object (see TypeOf)
+var Undefined = new(Object)
+
+//TypeOf returns the js typeof of a fake object: "string", "number" or "boolean" for a primitive
+// value, "function" for a function object, "null" for nil, "undefined" for Undefined, and "object" otherwise
+func TypeOf(o *Object) string {
+	switch {
+	case o == nil:
+		return "null"
+	case o == Undefined:
+		return "undefined"
+	case o.Func != nil:
+		return "function"
+	}
+	switch o.Value.(type) {
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case int, int64, uint64, float64:
+		return "number"
+	}
+	return "object"
+}
+
+//Of returns a fake object holding the go value 'v' (or 'v' itself if it is an *Object).
+//
+// a go func is a function object: its args are converted to the types of its params, and its
+// first result, if any, is the result of the call.
+func Of(v interface{}) *Object {
+	if o, ok := v.(*Object); ok {
+		return o
+	}
+	if fn := reflect.ValueOf(v); fn.Kind() == reflect.Func && !fn.IsNil() {
+		return &Object{Value: v, Func: func(args ...*Object) *Object { return callFunc(fn, args) }}
+	}
+	return &Object{Value: v}
+}
+
+//callFunc calls the go func 'fn' with the objects 'args'
+func callFunc(fn reflect.Value, args []*Object) *Object {
+	t := fn.Type()
+	in := make([]reflect.Value, 0, t.NumIn())
+	for i := 0; i < t.NumIn(); i++ {
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			for j := i; j < len(args); j++ {
+				in = append(in, argValue(args, j, t.In(i).Elem()))
+			}
+			break
+		}
+		in = append(in, argValue(args, i, t.In(i)))
+	}
+	out := fn.Call(in)
+	if len(out) == 0 {
+		return new(Object)
+	}
+	return Of(out[0].Interface())
+}
+
+var objectType = reflect.TypeOf((*Object)(nil))
+
+//argValue converts the arg 'i' (undefined if it is missing) to the type 't'
+func argValue(args []*Object, i int, t reflect.Type) reflect.Value {
+	switch {
+	case t == objectType && i >= len(args):
+		return reflect.ValueOf(new(Object))
+	case t == objectType:
+		return reflect.ValueOf(args[i])
+	case i >= len(args):
+		return reflect.Zero(t)
+	}
+	v := reflect.ValueOf(args[i].Interface())
+	switch {
+	case v.Type().AssignableTo(t):
+		return v
+	case v.Type().ConvertibleTo(t):
+		return v.Convert(t)
+	}
+	return reflect.Zero(t)
+}
+
+//Return sets the result of the calls of 'method' to Of(value)
+func (o *Object) Return(method string, value interface{}) *Object {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.Results == nil {
+		o.Results = make(map[string]*Object)
+	}
+	o.Results[method] = Of(value)
+	return o
+}
+
+//Answer sets the method property 'method' to a function object: the result of a call of
+// 'method' is Of(f(args)), it can depend on the arguments, or count the calls.
+//
+// Return has precedence over Answer.
+func (o *Object) Answer(method string, f func(args ...*Object) interface{}) *Object {
+	o.Set(method, &Object{Func: func(args ...*Object) *Object { return Of(f(args...)) }})
+	return o
+}
+
+//CallsTo returns the calls of 'method', in order
+func (o *Object) CallsTo(method string) (calls []Call) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, c := range o.Calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return
+}
+
+//Reset forgets the calls, results and properties of the object
+func (o *Object) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.Calls, o.Results, o.Props = nil, nil, nil
+}
+
+//Fire calls the function arguments (the go funcs, or the function objects) of the recorded calls
+// of 'method', in order, with the arguments 'args', and returns the number of calls.
+//
+// it fires the event handlers registered with x.On(...) (method "on").
+func (o *Object) Fire(method string, args ...interface{}) (n int) {
+	for _, c := range o.CallsTo(method) {
+		for _, a := range c.Args {
+			if f := Of(a); f.Func != nil {
+				f.Invoke(args...)
+				n++
+			}
+		}
+	}
+	return
+}
+
+//call records a call, and returns its result
+func (o *Object) call(method string, args []interface{}) *Object {
+	o.mu.Lock()
+	o.Calls = append(o.Calls, Call{Method: method, Args: args})
+	r, ok := o.Results[method]
+	prop := o.Props[method]
+	switch {
+	case ok:
+	case method == InvokeMethod || method == NewMethod:
+	case prop != nil && prop.Func != nil:
+	default:
+		// the same empty result for every call
+		if o.Results == nil {
+			o.Results = make(map[string]*Object)
+		}
+		r, ok = new(Object), true
+		o.Results[method] = r
+	}
+	o.mu.Unlock() // the funcs may call the object again
+	switch {
+	case ok:
+		return r
+	case method == InvokeMethod || method == NewMethod:
+		if o.Func != nil {
+			return o.Func(objects(args)...)
+		}
+		if method == InvokeMethod {
+			panic(fmt.Errorf("fake Invoke: the object is not a function"))
+		}
+		return new(Object) // a new instance for every call
+	}
+	return prop.Func(objects(args)...)
+}
+
+//objects returns the fake objects of the go values 'args'
+func objects(args []interface{}) []*Object {
+	objects := make([]*Object, len(args))
+	for i, a := range args {
+		objects[i] = Of(a)
+	}
+	return objects
+}
+
+//Get returns the property 'key', an empty object if it is not set
+func (o *Object) Get(key string) *Object {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if p, ok := o.Props[key]; ok {
+		return p
+	}
+	return o.set(key, new(Object))
+}
+
+//Set sets the property 'key' to Of(value)
+func (o *Object) Set(key string, value interface{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.set(key, Of(value))
+}
+
+//set sets the property 'key' to 'p', and returns it
+func (o *Object) set(key string, p *Object) *Object {
+	if o.Props == nil {
+		o.Props = make(map[string]*Object)
+	}
+	o.Props[key] = p
+	return p
+}
+
+//Delete removes the property 'key'
+func (o *Object) Delete(key string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.Props, key)
+}
+
+//Call records the call of the method 'name', and returns its result
+func (o *Object) Call(name string, args ...interface{}) *Object { return o.call(name, args) }
+
+//Invoke records the call of the object, and returns its result, or the one of its Func.
+//
+// it panics if the object is not a function, and has no result for InvokeMethod.
+func (o *Object) Invoke(args ...interface{}) *Object { return o.call(InvokeMethod, args) }
+
+//New records the call of the object as a constructor, and returns its result, or the one of its
+// Func, or a new empty object
+func (o *Object) New(args ...interface{}) *Object { return o.call(NewMethod, args) }
+
+//Bool returns the value of the object as a bool
+func (o *Object) Bool() bool {
+	b, _ := o.Value.(bool)
+	return b
+}
+
+//String returns the value of the object as a string ("" for a non primitive object)
+func (o *Object) String() string {
+	switch v := o.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+//Int returns the value of the object as an int
+func (o *Object) Int() int { return int(o.Float()) }
+
+//Int64 returns the value of the object as an int64
+func (o *Object) Int64() int64 { return int64(o.Float()) }
+
+//Uint64 returns the value of the object as an uint64
+func (o *Object) Uint64() uint64 { return uint64(o.Float()) }
+
+//Float returns the value of the object as a float64 (0 if it is not a number)
+func (o *Object) Float() float64 {
+	switch v := o.Value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float64:
+		return v
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+	return 0
+}
+
+//Interface returns the go value of a primitive object, or the object itself
+func (o *Object) Interface() interface{} {
+	if o.Value != nil {
+		return o.Value
+	}
+	return o
+}
+
+//MakeWrapper returns a fake object holding the go value 'i'
+func MakeWrapper(i interface{}) *Object { return &Object{Value: i} }
@@ -0,0 +1,100 @@
+package js
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func ExampleObject() {
+	o := new(Object)
+	o.Return("css", "red")
+	fmt.Println(o.Call("css", "color").String())
+	fmt.Println(o.Call("width").Int(), o.Call("width") == o.Call("width"))
+	fmt.Println(o.CallsTo("css"), len(o.Calls))
+	//Output:
+	// red
+	// 0 true
+	// [{css [color]}] 4
+}
+
+func ExampleGlobal() {
+	defer Global.Reset()
+	Global.Get("jQuery").Get("fn").Set("jquery", "3.7.1")
+	fmt.Println(Global.Get("jQuery").Get("fn").Get("jquery"))
+	Global.Get("jQuery").New("p")
+	fmt.Println(Global.Get("jQuery").CallsTo("new"))
+	//Output:
+	// 3.7.1
+	// [{new [p]}]
+}
+
+func ExampleTypeOf() {
+	for _, o := range []*Object{Of("red"), Of(3), Of(true), Of(func() {}), new(Object), nil, Undefined} {
+		fmt.Println(TypeOf(o))
+	}
+	//Output:
+	// string
+	// number
+	// boolean
+	// function
+	// object
+	// null
+	// undefined
+}
+
+func ExampleObject_Answer() {
+	o := new(Object)
+	calls := 0
+	o.Answer("attr", func(args ...*Object) interface{} {
+		calls++
+		return fmt.Sprintf("%s-%d", args[0], calls)
+	})
+	fmt.Println(o.Call("attr", "id"), o.Call("attr", "name"))
+	//Output:
+	// id-1 name-2
+}
+
+func ExampleObject_Fire() {
+	o := new(Object)
+	o.Call("on", "click", func(e *Object) { fmt.Println("clicked", e.Get("which")) })
+	e := new(Object)
+	e.Set("which", 1)
+	fmt.Println(o.Fire("on", e), o.Fire("off"))
+	//Output:
+	// clicked 1
+	// 1 0
+}
+
+//TestUseGlobal runs parallel tests using Global, and fake objects called concurrently
+func TestUseGlobal(t *testing.T) {
+	for _, name := range []string{"a", "b", "c", "d"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			UseGlobal(t)
+			Global.Set("test", name)
+			Global.Get("jQuery").Call("css", name)
+			if got := Global.Get("test").String(); got != name {
+				t.Errorf("Global test = %v, want %v", got, name)
+			}
+			if calls := Global.Get("jQuery").CallsTo("css"); len(calls) != 1 {
+				t.Errorf("css calls = %v, want 1", calls)
+			}
+		})
+	}
+
+	o := new(Object)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			o.Call("css", "color").Get("length")
+		}()
+	}
+	wg.Wait()
+	if n := len(o.CallsTo("css")); n != 10 {
+		t.Errorf("css calls = %v, want 10", n)
+	}
+}
@@ -0,0 +1,392 @@
+package apigen
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//the formats of the reference documentation (see Reference)
+const (
+	Markdown = "md"
+	HTML     = "html"
+)
+
+//Page is a page of the reference documentation
+type Page struct {
+	Name    string // file name ("JQuery.md")
+	Content []byte
+}
+
+//Reference renders the api into a browsable reference documentation, in the 'format' Markdown or HTML.
+//
+// The first page is the index: the types, the funcs, and the mapping from the js names to the go
+// names. Then there is one page per type: its properties, constructor and methods. Every func is
+// documented with its go signature, its js name (linked to its reference documentation, see
+// Func.URL), its description, and its deprecation.
+func Reference(api *Api, format string) ([]Page, error) {
+	var execute func(name string, data interface{}) ([]byte, error)
+	switch format {
+	case Markdown:
+		execute = func(name string, data interface{}) ([]byte, error) {
+			var buf bytes.Buffer
+			err := markdownTemplates.ExecuteTemplate(&buf, name, data)
+			return buf.Bytes(), err
+		}
+	case HTML:
+		execute = func(name string, data interface{}) ([]byte, error) {
+			var buf bytes.Buffer
+			err := htmlTemplates.ExecuteTemplate(&buf, name, data)
+			return buf.Bytes(), err
+		}
+	default:
+		return nil, fmt.Errorf("unknown reference format %q, expecting %q or %q", format, Markdown, HTML)
+	}
+
+	ref := newReference(api, format)
+	index, err := execute("index", ref)
+	if err != nil {
+		return nil, err
+	}
+	pages := []Page{{Name: "index." + format, Content: index}}
+	for _, t := range ref.Types {
+		content, err := execute("type", t)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, Page{Name: t.File, Content: content})
+	}
+	return pages, nil
+}
+
+//reference is the data of the reference templates
+type reference struct {
+	Name    string
+	Index   string // index file name
+	Types   []*refType
+	Funcs   []*refFunc // funcs without receiver type
+	Unions  []*refUnion
+	Mapping []*refFunc // all the funcs, sorted by js name
+}
+
+type refType struct {
+	Name, File, Index string
+	Summary           string // first sentence of the description
+	Description       string
+	Parents           []*refType
+	Properties        []refProperty
+	Constructor       *refFunc
+	Methods           []*refFunc
+}
+
+type refProperty struct{ Name, JS, Type, Description string }
+
+type refUnion struct{ Name, Description, Alternatives string }
+
+type refFunc struct {
+	Name, Type  string // go name, and receiver type name ("" for a func)
+	File        string // file of the page documenting it
+	Signature   string // go signature
+	JS          string // js name (".addClass()", "jQuery.ajax()")
+	URL         string
+	Description string
+	Deprecated  string
+	Anchor      string // anchor of the func in its page
+}
+
+//GoName returns the go name of the func, qualified by its receiver type
+func (f *refFunc) GoName() string {
+	if f.Type != "" {
+		return f.Type + "." + f.Name
+	}
+	return f.Name
+}
+
+//newReference collects the documentation of the api
+func newReference(api *Api, format string) *reference {
+	ref := &reference{Name: api.Name, Index: "index." + format}
+	types := make(map[string]*refType)
+	for _, t := range api.Types {
+		rt := &refType{
+			Name:        t.Name,
+			File:        t.Name + "." + format,
+			Index:       ref.Index,
+			Summary:     summary(t.Description),
+			Description: t.Description,
+		}
+		for _, p := range t.Properties {
+			rt.Properties = append(rt.Properties, refProperty{Name: p.Name, JS: p.JS, Type: exprString(p.Type), Description: p.Description})
+		}
+		types[t.Name] = rt
+		ref.Types = append(ref.Types, rt)
+	}
+	for _, t := range api.Types {
+		for _, p := range t.Parents {
+			if rp, ok := types[p.Name]; ok {
+				types[t.Name].Parents = append(types[t.Name].Parents, rp)
+			}
+		}
+	}
+
+	// the js path of the global vars, receivers of the static funcs
+	globals := make(map[string]string)
+	for _, v := range api.Vars {
+		if path, ok := globalPath(v.Value); ok && path != "" {
+			globals[v.Name] = path
+		}
+	}
+	for _, t := range api.Types {
+		if c := t.Constructor; c != nil {
+			decl := ConstructorDecl(t)
+			types[t.Name].Constructor = &refFunc{
+				Name:        decl.Name.Name,
+				File:        types[t.Name].File,
+				Signature:   signature(decl),
+				JS:          "new " + c.JS + "()",
+				Description: c.Description,
+				Anchor:      decl.Name.Name,
+			}
+		}
+	}
+	for _, f := range api.Funcs {
+		rf := &refFunc{
+			Name:        f.Name,
+			File:        ref.Index,
+			Signature:   signature(FuncDecl(f)),
+			JS:          f.JS + "()",
+			URL:         f.URL,
+			Description: f.Description,
+			Deprecated:  f.Deprecated,
+			Anchor:      f.Name,
+		}
+		if f.ReceiverType == nil {
+			if path, ok := globals[f.ReceiverName]; ok {
+				rf.JS = path + "." + rf.JS
+			}
+			ref.Funcs = append(ref.Funcs, rf)
+		} else if rt, ok := types[receiverName(f.ReceiverType)]; ok {
+			rf.JS, rf.Type, rf.File, rf.Anchor = "."+rf.JS, rt.Name, rt.File, rt.Name+"."+f.Name
+			rt.Methods = append(rt.Methods, rf)
+		} else {
+			continue
+		}
+		ref.Mapping = append(ref.Mapping, rf)
+	}
+	sort.SliceStable(ref.Mapping, func(i, j int) bool {
+		return strings.TrimPrefix(ref.Mapping[i].JS, ".") < strings.TrimPrefix(ref.Mapping[j].JS, ".")
+	})
+	for _, u := range api.Unions {
+		names := make([]string, len(u.Alternatives))
+		for i, a := range u.Alternatives {
+			names[i] = a.Name
+		}
+		ref.Unions = append(ref.Unions, &refUnion{Name: u.Name, Description: summary(u.Description), Alternatives: strings.Join(names, ", ")})
+	}
+	return ref
+}
+
+//signature returns the go signature of a func declaration (without its body and doc)
+func signature(fd *ast.FuncDecl) string {
+	decl := *fd
+	decl.Doc, decl.Body = nil, nil
+	var buf bytes.Buffer
+	printer.Fprint(&buf, token.NewFileSet(), &decl)
+	return buf.String()
+}
+
+//exprString returns the go source of 'e'
+func exprString(e ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, token.NewFileSet(), e)
+	return buf.String()
+}
+
+//summary returns the first sentence of a description
+func summary(text string) string {
+	text = strings.TrimSpace(text)
+	if i := strings.Index(text, "\n\n"); i >= 0 {
+		text = text[:i]
+	}
+	if i := strings.Index(text, ". "); i >= 0 {
+		text = text[:i+1]
+	}
+	return oneline(text)
+}
+
+//oneline joins the lines of 'text', and collapses its spaces
+func oneline(text string) string { return strings.Join(strings.Fields(text), " ") }
+
+//refBlock is a block of a description: a paragraph, or preformatted lines
+type refBlock struct {
+	Code bool
+	Text string
+}
+
+//blocks splits a description into paragraphs and preformatted blocks (lines starting with a space or a tab)
+func blocks(text string) (blocks []refBlock) {
+	var cur *refBlock
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		code := line != "" && (line[0] == ' ' || line[0] == '\t')
+		if line == "" || cur == nil || cur.Code != code {
+			if cur != nil {
+				blocks = append(blocks, *cur)
+				cur = nil
+			}
+			if line == "" {
+				continue
+			}
+			cur = &refBlock{Code: code}
+		} else {
+			cur.Text += "\n"
+		}
+		if code {
+			line = line[1:]
+		}
+		cur.Text += line
+	}
+	if cur != nil {
+		blocks = append(blocks, *cur)
+	}
+	return
+}
+
+//markdown returns a description in markdown: preformatted blocks are fenced
+func markdown(text string) string {
+	var parts []string
+	for _, b := range blocks(text) {
+		if b.Code {
+			parts = append(parts, "```js\n"+b.Text+"\n```")
+		} else {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+var markdownTemplates = template.Must(template.New("").Funcs(template.FuncMap{"markdown": markdown, "oneline": oneline}).Parse(`
+{{- define "func" -}}
+### <a id="{{.Anchor}}"></a>{{.Name}}
+
+` + "```go\n{{.Signature}}\n```" + `
+
+{{if .URL}}JS: [` + "`{{.JS}}`" + `]({{.URL}}){{else}}JS: ` + "`{{.JS}}`" + `{{end}}
+{{if .Deprecated}}
+> **Deprecated:** {{.Deprecated}}.
+{{end}}{{with markdown .Description}}
+{{.}}
+{{end}}
+{{- end}}
+
+{{- define "index" -}}
+# Package {{.Name}}
+{{if .Types}}
+## Types
+
+{{range .Types}}- [{{.Name}}]({{.File}}){{with .Summary}}: {{.}}{{end}}
+{{end}}{{end}}{{if .Unions}}
+## Unions
+
+{{range .Unions}}- ` + "`{{.Name}}`" + ` is one of {{.Alternatives}}.{{with .Description}} {{.}}{{end}}
+{{end}}{{end}}{{if .Funcs}}
+## Functions
+
+{{range $i, $f := .Funcs}}{{if $i}}
+{{end}}{{template "func" $f}}{{end}}{{end}}{{if .Mapping}}
+## JavaScript to Go
+
+| JavaScript | Go |
+| --- | --- |
+{{range .Mapping}}| ` + "`{{.JS}}`" + ` | [{{.GoName}}]({{.File}}#{{.Anchor}}){{if .Deprecated}} (deprecated){{end}} |
+{{end}}{{end}}
+{{- end}}
+
+{{- define "type" -}}
+# {{.Name}}
+
+[Index]({{.Index}})
+{{with markdown .Description}}
+{{.}}
+{{end}}{{if .Parents}}
+{{.Name}} embeds {{range $i, $p := .Parents}}{{if $i}}, {{end}}[{{$p.Name}}]({{$p.File}}){{end}}, and inherits their methods.
+{{end}}{{if .Properties}}
+## Properties
+
+| Go | JavaScript | Type | Description |
+| --- | --- | --- | --- |
+{{range .Properties}}| {{.Name}} | ` + "`{{.JS}}`" + ` | ` + "`{{.Type}}`" + ` | {{oneline .Description}} |
+{{end}}{{end}}{{with .Constructor}}
+## Constructor
+
+{{template "func" .}}{{end}}{{if .Methods}}
+## Methods
+
+{{range $i, $f := .Methods}}{{if $i}}
+{{end}}{{template "func" $f}}{{end}}{{end}}
+{{- end}}
+`))
+
+var htmlTemplates = htmltemplate.Must(htmltemplate.New("").Funcs(htmltemplate.FuncMap{"blocks": blocks}).Parse(`
+{{- define "description" -}}
+{{range blocks .}}{{if .Code}}<pre><code>{{.Text}}</code></pre>{{else}}<p>{{.Text}}</p>{{end}}
+{{end}}
+{{- end}}
+
+{{- define "func" -}}
+<h3 id="{{.Anchor}}">{{.Name}}</h3>
+<pre><code>{{.Signature}}</code></pre>
+<p>JS: {{if .URL}}<a href="{{.URL}}"><code>{{.JS}}</code></a>{{else}}<code>{{.JS}}</code>{{end}}</p>
+{{if .Deprecated}}<p class="deprecated"><strong>Deprecated:</strong> {{.Deprecated}}.</p>
+{{end}}{{template "description" .Description}}
+{{- end}}
+
+{{- define "index" -}}
+<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Package {{.Name}}</title></head>
+<body>
+<h1>Package {{.Name}}</h1>
+{{if .Types}}<h2>Types</h2>
+<ul>
+{{range .Types}}<li><a href="{{.File}}">{{.Name}}</a>{{with .Summary}}: {{.}}{{end}}</li>
+{{end}}</ul>
+{{end}}{{if .Unions}}<h2>Unions</h2>
+<ul>
+{{range .Unions}}<li><code>{{.Name}}</code> is one of {{.Alternatives}}.{{with .Description}} {{.}}{{end}}</li>
+{{end}}</ul>
+{{end}}{{if .Funcs}}<h2>Functions</h2>
+{{range .Funcs}}{{template "func" .}}{{end}}{{end}}{{if .Mapping}}<h2>JavaScript to Go</h2>
+<table>
+<tr><th>JavaScript</th><th>Go</th></tr>
+{{range .Mapping}}<tr><td><code>{{.JS}}</code></td><td><a href="{{.File}}#{{.Anchor}}">{{.GoName}}</a>{{if .Deprecated}} (deprecated){{end}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+{{end}}
+
+{{- define "type" -}}
+<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Name}}</title></head>
+<body>
+<h1>{{.Name}}</h1>
+<p><a href="{{.Index}}">Index</a></p>
+{{template "description" .Description}}
+{{- if .Parents}}<p>{{.Name}} embeds {{range $i, $p := .Parents}}{{if $i}}, {{end}}<a href="{{$p.File}}">{{$p.Name}}</a>{{end}}, and inherits their methods.</p>
+{{end}}{{if .Properties}}<h2>Properties</h2>
+<table>
+<tr><th>Go</th><th>JavaScript</th><th>Type</th><th>Description</th></tr>
+{{range .Properties}}<tr><td>{{.Name}}</td><td><code>{{.JS}}</code></td><td><code>{{.Type}}</code></td><td>{{.Description}}</td></tr>
+{{end}}</table>
+{{end}}{{with .Constructor}}<h2>Constructor</h2>
+{{template "func" .}}{{end}}{{if .Methods}}<h2>Methods</h2>
+{{range .Methods}}{{template "func" .}}{{end}}{{end}}</body>
+</html>
+{{end}}
+`))
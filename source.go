@@ -0,0 +1,152 @@
+package apigen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/printer"
+	"go/token"
+	"io"
+	"strings"
+)
+
+//Source generates the formatted go source file for the whole api.
+//
+// Generated nodes have no position, and go/printer cannot place their doc comments
+// correctly (especially for struct fields). Source prints each declaration with its doc
+// comments, and then formats the result.
+//
+// The file starts with the standard "Code generated ... DO NOT EDIT." header, so that
+// ParseDecls can tell it from hand written files.
+//
+// The file is gopherjs code: api.JSImport must be a gopherjs compatible package, the js packages of
+// the wasm toolchains are rejected (generate the Wasm target instead, see TargetSource).
+func Source(api *Api) ([]byte, error) {
+	if wasmImports[api.JSImport] {
+		return nil, fmt.Errorf("cannot generate gopherjs code with the js package %v: generate the %v target instead", api.JSImport, Wasm.Name)
+	}
+	return fileSource(api.Generator, File(api), "")
+}
+
+//fileSource generates the formatted go source of 'file', with an optional build constraint
+func fileSource(generator string, file *ast.File, constraint string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(header(generator))
+	if constraint != "" {
+		fmt.Fprintf(&buf, "//go:build %s\n\n", constraint)
+	}
+	fmt.Fprintf(&buf, "package %s\n\n", file.Name.Name)
+	for _, d := range file.Decls {
+		if err := fprintDecl(&buf, d); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\n\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+//header returns the "Code generated" header of the files generated by 'generator' (default to "apigen")
+func header(generator string) string {
+	if generator == "" {
+		generator = "apigen"
+	}
+	return fmt.Sprintf("// Code generated by %s. DO NOT EDIT.\n\n", generator)
+}
+
+//fprintDecl prints a single declaration, and its doc comments
+func fprintDecl(w io.Writer, d ast.Decl) error {
+	fset := token.NewFileSet()
+	switch d := d.(type) {
+	case *ast.FuncDecl:
+		fprintDoc(w, d.Doc)
+		nodoc := *d
+		nodoc.Doc = nil
+		return printer.Fprint(w, fset, &nodoc)
+
+	case *ast.GenDecl:
+		fprintDoc(w, d.Doc)
+		if st := structOf(d); st != nil {
+			return fprintStruct(w, d.Specs[0].(*ast.TypeSpec).Name.Name, st)
+		}
+		if d.Lparen.IsValid() && d.Tok != token.IMPORT {
+			return fprintBlock(w, d)
+		}
+		nodoc := *d
+		nodoc.Doc = nil
+		return printer.Fprint(w, fset, &nodoc)
+
+	default:
+		return printer.Fprint(w, fset, d)
+	}
+}
+
+//structOf return the struct type declared by 'd', if d is a single struct type declaration
+func structOf(d *ast.GenDecl) *ast.StructType {
+	if d.Tok != token.TYPE || len(d.Specs) != 1 {
+		return nil
+	}
+	st, _ := d.Specs[0].(*ast.TypeSpec).Type.(*ast.StructType)
+	return st
+}
+
+//fprintStruct prints a struct type declaration one field at a time, with their doc comments
+func fprintStruct(w io.Writer, name string, st *ast.StructType) error {
+	fset := token.NewFileSet()
+	fmt.Fprintf(w, "type %s struct {\n", name)
+	for _, f := range st.Fields.List {
+		fprintDoc(w, f.Doc)
+		names := make([]string, len(f.Names))
+		for i, n := range f.Names {
+			names[i] = n.Name
+		}
+		if len(names) > 0 {
+			fmt.Fprintf(w, "%s ", strings.Join(names, ", "))
+		}
+		if err := printer.Fprint(w, fset, f.Type); err != nil {
+			return err
+		}
+		if f.Tag != nil {
+			fmt.Fprintf(w, " %s", f.Tag.Value)
+		}
+		fmt.Fprintln(w)
+	}
+	_, err := fmt.Fprint(w, "}")
+	return err
+}
+
+//fprintBlock prints a parenthesized declaration one spec at a time, with their doc comments
+func fprintBlock(w io.Writer, d *ast.GenDecl) error {
+	fset := token.NewFileSet()
+	fmt.Fprintf(w, "%s (\n", d.Tok)
+	for _, spec := range d.Specs {
+		var node ast.Node = spec
+		switch spec := spec.(type) {
+		case *ast.ValueSpec:
+			fprintDoc(w, spec.Doc)
+			nodoc := *spec
+			nodoc.Doc = nil
+			node = &nodoc
+		case *ast.TypeSpec:
+			fprintDoc(w, spec.Doc)
+			nodoc := *spec
+			nodoc.Doc = nil
+			node = &nodoc
+		}
+		if err := printer.Fprint(w, fset, node); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+	_, err := fmt.Fprint(w, ")")
+	return err
+}
+
+func fprintDoc(w io.Writer, g *ast.CommentGroup) {
+	if g == nil {
+		return
+	}
+	for _, c := range g.List {
+		fmt.Fprintln(w, c.Text)
+	}
+}
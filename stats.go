@@ -0,0 +1,139 @@
+package apigen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+//Coverage counts the funcs with fully typed params
+type Coverage struct {
+	Funcs int `json:"funcs"` // number of funcs
+	Typed int `json:"typed"` // number of funcs without any interface{} or *js.Object param
+}
+
+//Percent returns the percentage of typed funcs (100 if there is no func)
+func (c Coverage) Percent() float64 {
+	if c.Funcs == 0 {
+		return 100
+	}
+	return 100 * float64(c.Typed) / float64(c.Funcs)
+}
+
+func (c *Coverage) add(typed bool) {
+	c.Funcs++
+	if typed {
+		c.Typed++
+	}
+}
+
+//Stats measures how much of an api is typed
+type Stats struct {
+	Total      Coverage            `json:"total"`
+	Types      map[string]Coverage `json:"types"`      // by receiver type, "" for the funcs
+	Categories map[string]Coverage `json:"categories"` // by documentation category (see Func.Categories)
+}
+
+//ComputeStats computes the typed funcs of the api.
+//
+// a func is typed if none of its params is an interface{}, a ...interface{}, or a *js.Object
+func ComputeStats(api *Api) *Stats {
+	s := &Stats{
+		Types:      make(map[string]Coverage),
+		Categories: make(map[string]Coverage),
+	}
+	for _, f := range api.Funcs {
+		typed := TypedParams(f)
+		s.Total.add(typed)
+
+		var receiver string
+		if f.ReceiverType != nil {
+			receiver = types.ExprString(f.ReceiverType)
+		}
+		c := s.Types[receiver]
+		c.add(typed)
+		s.Types[receiver] = c
+
+		for _, cat := range f.Categories {
+			c := s.Categories[cat]
+			c.add(typed)
+			s.Categories[cat] = c
+		}
+	}
+	return s
+}
+
+//TypedParams returns true if none of the params of 'f' is an interface{}, a ...interface{}, or a *js.Object
+func TypedParams(f *Func) bool {
+	if f.Params == nil {
+		return true
+	}
+	for _, p := range f.Params.List {
+		t := p.Type
+		if e, ok := t.(*ast.Ellipsis); ok {
+			t = e.Elt
+		}
+		switch t := t.(type) {
+		case *ast.InterfaceType:
+			if t.Methods == nil || len(t.Methods.List) == 0 {
+				return false
+			}
+		case *ast.StarExpr:
+			if types.ExprString(t) == types.ExprString(JSObject) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+//WriteText writes the stats as a table, compared to the 'previous' ones (if not nil)
+func (s *Stats) WriteText(w io.Writer, previous *Stats) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	row := func(name string, c Coverage, prev map[string]Coverage, key string) {
+		fmt.Fprintf(tw, "%v\t%d\t%d\t%.1f%%", name, c.Funcs, c.Typed, c.Percent())
+		if previous != nil {
+			if p, exists := prev[key]; exists {
+				fmt.Fprintf(tw, "\t%+.1f", c.Percent()-p.Percent())
+			} else {
+				fmt.Fprint(tw, "\tnew")
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	var prevTotal, prevTypes, prevCategories map[string]Coverage // nil without previous stats
+	if previous != nil {
+		prevTotal = map[string]Coverage{"": previous.Total}
+		prevTypes, prevCategories = previous.Types, previous.Categories
+	}
+
+	fmt.Fprint(tw, "\tfuncs\ttyped\t%")
+	if previous != nil {
+		fmt.Fprint(tw, "\tdelta")
+	}
+	fmt.Fprintln(tw)
+	row("total", s.Total, prevTotal, "")
+	for _, name := range sortedNames(s.Types) {
+		label := "type " + name
+		if name == "" {
+			label = "funcs"
+		}
+		row(label, s.Types[name], prevTypes, name)
+	}
+	for _, name := range sortedNames(s.Categories) {
+		row("category "+name, s.Categories[name], prevCategories, name)
+	}
+	return tw.Flush()
+}
+
+func sortedNames(m map[string]Coverage) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
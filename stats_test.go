@@ -0,0 +1,28 @@
+package apigen
+
+import (
+	"go/ast"
+	"os"
+)
+
+func ExampleStats_WriteText() {
+	typed := &ast.FieldList{List: []*ast.Field{&ast.Field{Type: &ast.Ident{Name: "string"}}}}
+	untyped := &ast.FieldList{List: []*ast.Field{&ast.Field{Type: &ast.Ellipsis{Elt: EmptyInterface()}}}}
+	foo := &ast.Ident{Name: "Foo"}
+	api := &Api{Funcs: []*Func{
+		&Func{Name: "A", ReceiverType: foo, Params: typed, Categories: []string{"attributes"}},
+		&Func{Name: "B", ReceiverType: foo, Params: untyped, Categories: []string{"attributes"}},
+		&Func{Name: "C", Params: &ast.FieldList{List: []*ast.Field{&ast.Field{Type: JSObject}}}, Categories: []string{"ajax"}},
+		&Func{Name: "D", Params: &ast.FieldList{}, Categories: []string{"ajax"}},
+	}}
+	previous := ComputeStats(api)
+	api.Funcs[1].Params = typed
+	ComputeStats(api).WriteText(os.Stdout, previous)
+	//Output:
+	//                      funcs  typed  %       delta
+	// total                4      3      75.0%   +25.0
+	// funcs                2      1      50.0%   +0.0
+	// type Foo             2      2      100.0%  +50.0
+	// category ajax        2      1      50.0%   +0.0
+	// category attributes  2      2      100.0%  +50.0
+}
@@ -0,0 +1,266 @@
+package apigen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"path"
+	"reflect"
+	"strconv"
+)
+
+//Target is a toolchain the api can be generated for (see TargetSource).
+//
+// Every target declares the same exported surface: js objects are of type Object, an alias of
+// the js object type of the target, so that a package using the binding builds with both.
+type Target struct {
+	Name       string                         // target name, the suffix of the generated file (jquery_wasm.go)
+	Constraint string                         // build constraint of the generated file
+	Import     string                         // import path of the js package
+	Object     string                         // go type of a js object, aliased as Object
+	Rewrite    func(api *Api, file *ast.File) // optional, rewrites the gopherjs bodies for the target
+	GOOS       string                         // GOOS of the target toolchain, "" for the host one (see BuildContext)
+	GOARCH     string                         // GOARCH of the target toolchain, "" for the host one
+	Interfaces bool                           // declares the interfaces of the types, even if the api does not
+}
+
+//ObjectAlias is the name of the alias of the js object type, declared by TargetSource
+const ObjectAlias = "Object"
+
+var (
+	//GopherJS is the gopherjs target: the generated code as is
+	GopherJS = Target{
+		Name:       "gopherjs",
+		Constraint: "js && !wasm",
+		Import:     "github.com/gopherjs/gopherjs/js",
+		Object:     "*js.Object",
+		GOOS:       "js",
+		GOARCH:     "ecmascript",
+	}
+	//Wasm is the GOOS=js GOARCH=wasm target, using syscall/js
+	Wasm = Target{
+		Name:       "wasm",
+		Constraint: "js && wasm",
+		Import:     "syscall/js",
+		Object:     "js.Value",
+		Rewrite:    RewriteWasm,
+		GOOS:       "js",
+		GOARCH:     "wasm",
+	}
+	//Fake is the target of the regular go toolchain, using the in-memory js package of jsfake,
+	// to unit test the code using the binding: FakeFoo returns a Foo recording its calls (see RewriteFake)
+	Fake = Target{
+		Name:       "fake",
+		Constraint: "!js",
+		Import:     "github.com/ericaro/apigen/jsfake",
+		Object:     "*js.Object",
+		Rewrite:    RewriteFake,
+		Interfaces: true, // the code under test takes the interfaces, the tests give it the fakes
+	}
+	//Targets are the predefined targets
+	Targets = []Target{GopherJS, Wasm, Fake}
+
+	// the js packages of the wasm toolchains: the gopherjs code does not build with them
+	wasmImports = map[string]bool{Wasm.Import: true, "github.com/gopherjs/gopherwasm/js": true}
+)
+
+//LookupTarget returns the predefined target called 'name'
+func LookupTarget(name string) (Target, bool) {
+	for _, t := range Targets {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Target{}, false
+}
+
+//BuildContext returns the go build context of the target toolchain: the host one, with the GOOS and GOARCH
+// of the target, if any
+func (t Target) BuildContext() build.Context {
+	ctxt := build.Default
+	if t.GOOS != "" {
+		ctxt.GOOS, ctxt.GOARCH = t.GOOS, t.GOARCH
+	}
+	return ctxt
+}
+
+//TargetSource generates the formatted go source file of the api for the target 't'.
+//
+// The file is guarded by the target build constraint, imports the target js package instead of
+// api.JSImport, and declares Object, the alias of the target js object type, used instead of *js.Object everywhere.
+// Then the target rewrites the function bodies. The interfaces of the types are declared if either the api
+// or the target asks for them.
+func TargetSource(api *Api, t Target) ([]byte, error) {
+	if t.Interfaces && !api.Interfaces {
+		c := *api
+		c.Interfaces = true
+		api = &c
+	}
+	// the generated nodes share the api expressions: rewrite a copy
+	file := copyNode(File(api)).(*ast.File)
+
+	jsImport := api.JSImport
+	if jsImport == "" {
+		jsImport = GopherJS.Import
+	}
+	imports := file.Decls[0].(*ast.GenDecl)
+	for _, spec := range imports.Specs {
+		imp := spec.(*ast.ImportSpec)
+		if p, err := strconv.Unquote(imp.Path.Value); err == nil && p == jsImport {
+			imp.Path.Value = strconv.Quote(t.Import)
+			if path.Base(t.Import) != "js" {
+				imp.Name = &ast.Ident{Name: "js"}
+			}
+		}
+	}
+	rewriteExprs(file, func(e ast.Expr) ast.Expr {
+		if isJSObject(e) {
+			return &ast.Ident{Name: ObjectAlias}
+		}
+		return e
+	})
+	if t.Rewrite != nil {
+		t.Rewrite(api, file)
+	}
+
+	// the alias, after the imports
+	alias, err := parser.ParseExpr(t.Object)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object type %q of target %v: %v", t.Object, t.Name, err)
+	}
+	decls := append([]ast.Decl{imports}, &ast.GenDecl{
+		Tok: token.TYPE,
+		Doc: Doc(ObjectAlias + " is a javascript object."),
+		Specs: []ast.Spec{
+			&ast.TypeSpec{Name: &ast.Ident{Name: ObjectAlias}, Assign: token.Pos(1), Type: stripPos(alias)},
+		},
+	})
+	file.Decls = append(decls, file.Decls[1:]...)
+	return fileSource(api.Generator, file, t.Constraint)
+}
+
+//copyNode returns a deep copy of the ast node 'n'
+func copyNode(n ast.Node) ast.Node {
+	return deepCopy(reflect.ValueOf(n)).Interface().(ast.Node)
+}
+
+//deepCopy copies pointers, interfaces, slices and structs recursively
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		switch v.Interface().(type) {
+		case *ast.Object, *ast.Scope: // resolution data, not part of the tree
+			return v
+		}
+		c := reflect.New(v.Elem().Type())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+//stripPos removes the positions of a parsed expression, so that it prints like a generated one
+func stripPos(e ast.Expr) ast.Expr {
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			n.NamePos = token.NoPos
+		case *ast.StarExpr:
+			n.Star = token.NoPos
+		}
+		return true
+	})
+	return e
+}
+
+//isJSObject returns true if 'e' is *js.Object
+func isJSObject(e ast.Expr) bool {
+	star, ok := e.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Object" && isIdent(sel.X, "js")
+}
+
+//isObject returns true if 'e' is the Object alias, or *js.Object
+func isObject(e ast.Expr) bool { return isIdent(e, ObjectAlias) || isJSObject(e) }
+
+func isIdent(e ast.Expr, name string) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == name
+}
+
+//rewriteExprs replaces the types and values of 'root' by f(e).
+//
+// it covers the expressions found in generated code: the replacements are walked too, so f must
+// not return an expression it would replace again.
+func rewriteExprs(root ast.Node, f func(ast.Expr) ast.Expr) {
+	all := func(exprs []ast.Expr) {
+		for i, e := range exprs {
+			exprs[i] = f(e)
+		}
+	}
+	ast.Inspect(root, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Field:
+			n.Type = f(n.Type)
+		case *ast.Ellipsis:
+			if n.Elt != nil {
+				n.Elt = f(n.Elt)
+			}
+		case *ast.ArrayType:
+			n.Elt = f(n.Elt)
+		case *ast.MapType:
+			n.Key, n.Value = f(n.Key), f(n.Value)
+		case *ast.ValueSpec:
+			if n.Type != nil {
+				n.Type = f(n.Type)
+			}
+			all(n.Values)
+		case *ast.CallExpr:
+			all(n.Args)
+		case *ast.ReturnStmt:
+			all(n.Results)
+		case *ast.AssignStmt:
+			all(n.Rhs)
+		case *ast.KeyValueExpr:
+			n.Value = f(n.Value)
+		case *ast.TypeAssertExpr:
+			if n.Type != nil {
+				n.Type = f(n.Type)
+			}
+		}
+		return true
+	})
+}
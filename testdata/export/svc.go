@@ -0,0 +1,29 @@
+// Package svc is a go service exposed to javascript (see ExampleExportSource)
+package svc
+
+import "strings"
+
+// Counter counts things.
+type Counter struct{ n int }
+
+// NewCounter returns a counter starting at 'n'.
+func NewCounter(n int) *Counter { return &Counter{n} }
+
+// Inc increments the counter by 'step', and returns the new count.
+func (c *Counter) Inc(step int) int {
+	c.n += step
+	return c.n
+}
+
+// Join joins the words.
+func Join(words interface{}, sep string) string {
+	return strings.Join(words.([]string), sep)
+}
+
+// Log logs a message.
+func Log(msg string, values ...interface{}) {}
+
+// Split returns the head and the tail of 's'.
+func Split(s string) (head, tail string) { return s[:1], s[1:] }
+
+func internal() {}
@@ -0,0 +1,123 @@
+package apigen
+
+import (
+	"fmt"
+	"go/ast"
+	"io"
+	"strings"
+)
+
+//tsType returns the typescript type of the go type 'e'.
+//
+// 'types' are the names declared by the api (as is in typescript), the other named types are "any".
+func tsType(e ast.Expr, types map[string]bool) string {
+	switch e := e.(type) {
+	case *ast.Ident:
+		switch e.Name {
+		case "bool":
+			return "boolean"
+		case "string":
+			return "string"
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+			"float32", "float64", "byte", "rune":
+			return "number"
+		case "error":
+			return "Error"
+		}
+		if types[e.Name] {
+			return e.Name
+		}
+	case *ast.StarExpr:
+		if !isObject(e) {
+			return tsType(e.X, types)
+		}
+	case *ast.ParenExpr:
+		return tsType(e.X, types)
+	case *ast.ArrayType:
+		return tsElem(e.Elt, types) + "[]"
+	case *ast.Ellipsis:
+		return tsElem(e.Elt, types) + "[]"
+	case *ast.MapType:
+		return fmt.Sprintf("{ [key: string]: %s }", tsType(e.Value, types))
+	case *ast.FuncType:
+		result := "void"
+		if e.Results != nil && len(e.Results.List) == 1 && len(e.Results.List[0].Names) <= 1 {
+			result = tsType(e.Results.List[0].Type, types)
+		}
+		return fmt.Sprintf("(%s) => %s", tsParams(e.Params, types), result)
+	}
+	return "any"
+}
+
+//tsElem returns the typescript type of an array element, in parenthesis if needed
+func tsElem(e ast.Expr, types map[string]bool) string {
+	t := tsType(e, types)
+	if strings.ContainsAny(t, " |") {
+		return "(" + t + ")"
+	}
+	return t
+}
+
+//tsParams returns the typescript parameter list of the go params 'fields' (unnamed ones are arg0, arg1...)
+func tsParams(fields *ast.FieldList, types map[string]bool) string {
+	if fields == nil {
+		return ""
+	}
+	var params []string
+	for _, f := range fields.List {
+		names := f.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{&ast.Ident{Name: fmt.Sprintf("arg%d", len(params))}}
+		}
+		for _, n := range names {
+			if _, variadic := f.Type.(*ast.Ellipsis); variadic {
+				params = append(params, fmt.Sprintf("...%s: %s", n.Name, tsType(f.Type, types)))
+			} else {
+				params = append(params, fmt.Sprintf("%s: %s", n.Name, tsType(f.Type, types)))
+			}
+		}
+	}
+	return strings.Join(params, ", ")
+}
+
+//jsParams returns the params of 'f' as received by js: a converted variadic param is a single array (see Func)
+func jsParams(f *Func) *ast.FieldList {
+	if f.Params == nil || len(f.Params.List) == 0 {
+		return f.Params
+	}
+	last := len(f.Params.List) - 1
+	variadic, ok := f.Params.List[last].Type.(*ast.Ellipsis)
+	if !ok || last >= len(f.ConvertArgs) || f.ConvertArgs[last] == nil {
+		return f.Params
+	}
+	params := &ast.FieldList{List: append([]*ast.Field{}, f.Params.List...)}
+	params.List[last] = &ast.Field{Names: f.Params.List[last].Names, Type: &ast.ArrayType{Elt: variadic.Elt}}
+	return params
+}
+
+//tsResult returns the typescript result type of a func ("void" if there is none)
+func tsResult(f *Func, types map[string]bool) string {
+	switch {
+	case f.ReturnsReceiver:
+		return tsType(f.ReceiverType, types)
+	case f.ResultType == nil:
+		return "void"
+	default:
+		return tsType(f.ResultType, types)
+	}
+}
+
+//fprintJSDoc prints the description 'text' as a JSDoc comment, wrapped like Doc
+func fprintJSDoc(w io.Writer, indent, text string) {
+	g := Doc(text)
+	if g == nil {
+		return
+	}
+	fmt.Fprintf(w, "%s/**\n", indent)
+	for _, c := range g.List {
+		line := strings.TrimPrefix(c.Text, "//")
+		line = strings.Replace(line, "*/", "*\\/", -1)
+		fmt.Fprintf(w, "%s *%s\n", indent, line)
+	}
+	fmt.Fprintf(w, "%s */\n", indent)
+}
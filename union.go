@@ -0,0 +1,173 @@
+package apigen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+//TypeOfFunc is the name of the func returning the js typeof of an object (see UnionDecls)
+const TypeOfFunc = "jsTypeOf"
+
+//TypeOfDecl declares the func returning the js typeof of its argument, with "null" for null.
+//
+// It evaluates no js code (a strict content security policy forbids the Function constructor): gopherjs
+// converts the object to a go value of the matching type. The other targets replace the body.
+//
+//	func jsTypeOf(j *js.Object) string {
+//		if j == nil {
+//			return "null"
+//		}
+//		if j == js.Undefined {
+//			return "undefined"
+//		}
+//		switch j.Interface().(type) {
+//		case bool:
+//			return "boolean"
+//		...
+//		}
+//		return "object"
+//	}
+func TypeOfDecl() *ast.FuncDecl {
+	j := &ast.Ident{Name: "j"}
+	str := func(s string) ast.Expr { return &ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", s)} }
+	ifEqual := func(v ast.Expr, typeOf string) ast.Stmt {
+		return &ast.IfStmt{
+			Cond: &ast.BinaryExpr{X: j, Op: token.EQL, Y: v},
+			Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{str(typeOf)}}}},
+		}
+	}
+	var cases []ast.Stmt
+	for _, c := range []struct {
+		typ    ast.Expr
+		typeOf string
+	}{
+		{&ast.Ident{Name: "bool"}, "boolean"},
+		{&ast.Ident{Name: "float64"}, "number"},
+		{&ast.Ident{Name: "string"}, "string"},
+		{&ast.FuncType{
+			Params:  &ast.FieldList{List: []*ast.Field{{Type: &ast.Ellipsis{Elt: EmptyInterface()}}}},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: JSObject}}},
+		}, "function"},
+	} {
+		cases = append(cases, &ast.CaseClause{
+			List: []ast.Expr{c.typ},
+			Body: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{str(c.typeOf)}}},
+		})
+	}
+	return &ast.FuncDecl{
+		Doc:  Doc(TypeOfFunc + ` returns the js typeof of 'j', "null" for null.`),
+		Name: &ast.Ident{Name: TypeOfFunc},
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{List: []*ast.Field{{Names: []*ast.Ident{j}, Type: JSObject}}},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: &ast.Ident{Name: "string"}}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			ifEqual(&ast.Ident{Name: "nil"}, "null"),
+			ifEqual(&ast.SelectorExpr{X: &ast.Ident{Name: "js"}, Sel: &ast.Ident{Name: "Undefined"}}, "undefined"),
+			&ast.TypeSwitchStmt{
+				Assign: &ast.ExprStmt{X: &ast.TypeAssertExpr{X: &ast.CallExpr{Fun: &ast.SelectorExpr{X: j, Sel: &ast.Ident{Name: "Interface"}}}}},
+				Body:   &ast.BlockStmt{List: cases},
+			},
+			&ast.ReturnStmt{Results: []ast.Expr{str("object")}},
+		}},
+	}
+}
+
+//typeOfBody replaces the body of the typeof func of 'file' (see TypeOfDecl) for a target: return 'typeOf'
+func typeOfBody(file *ast.File, typeOf ast.Expr) {
+	if fd := funcDecl(file, TypeOfFunc); fd != nil {
+		fd.Body = &ast.BlockStmt{List: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{typeOf}}}}
+	}
+}
+
+//UnionMarker returns the name of the unexported method that seals the union interface
+func UnionMarker(u *Union) string { return "is" + u.Name }
+
+//UnionDecls generates the declarations of a union:
+//
+// the sealed interface, the marker method of each alternative, and the converter from *js.Object
+// (dispatching on the js typeof, see TypeOfDecl), that returns nil for null and undefined. The alternative
+// types themselves are declared by AlternativeDecl.
+func UnionDecls(u *Union) (decls []ast.Decl) {
+	marker := UnionMarker(u)
+
+	// the sealed interface
+	decls = append(decls, &ast.GenDecl{
+		Tok: token.TYPE,
+		Doc: Doc(u.Description),
+		Specs: []ast.Spec{
+			&ast.TypeSpec{
+				Name: &ast.Ident{Name: u.Name},
+				Type: &ast.InterfaceType{
+					Methods: &ast.FieldList{
+						List: []*ast.Field{
+							&ast.Field{
+								Names: []*ast.Ident{&ast.Ident{Name: marker}},
+								Type:  &ast.FuncType{Params: &ast.FieldList{}},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// the marker methods
+	for _, a := range u.Alternatives {
+		decls = append(decls, &ast.FuncDecl{
+			Recv: &ast.FieldList{List: []*ast.Field{&ast.Field{Type: &ast.Ident{Name: a.Name}}}},
+			Name: &ast.Ident{Name: marker},
+			Type: &ast.FuncType{Params: &ast.FieldList{}},
+			Body: &ast.BlockStmt{},
+		})
+	}
+
+	// the converter: switch jsTypeOf(j) { case "string": return String(j.String()) ... }
+	// null and undefined match no alternative, they are nil
+	j := &ast.Ident{Name: "j"}
+	cases := make([]ast.Stmt, len(u.Alternatives))
+	for i, a := range u.Alternatives {
+		cases[i] = &ast.CaseClause{
+			List: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", a.TypeOf)}},
+			Body: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{a.Convert(j)}}},
+		}
+	}
+	typeOf := &ast.CallExpr{Fun: &ast.Ident{Name: TypeOfFunc}, Args: []ast.Expr{j}}
+	decls = append(decls, &ast.FuncDecl{
+		Name: &ast.Ident{Name: WrapperName(u.Name, false)},
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{List: []*ast.Field{&ast.Field{Names: []*ast.Ident{j}, Type: JSObject}}},
+			Results: &ast.FieldList{List: []*ast.Field{&ast.Field{Type: &ast.Ident{Name: u.Name}}}},
+		},
+		Body: &ast.BlockStmt{
+			List: []ast.Stmt{
+				&ast.SwitchStmt{
+					Tag:  typeOf,
+					Body: &ast.BlockStmt{List: cases},
+				},
+				&ast.ReturnStmt{Results: []ast.Expr{&ast.Ident{Name: "nil"}}},
+			},
+		},
+	})
+	return
+}
+
+//AlternativeDecl declares the type of an alternative (type String string)
+//
+// it returns nil if the alternative type is declared elsewhere
+func AlternativeDecl(a *Alternative) *ast.GenDecl {
+	if a.Type == nil {
+		return nil
+	}
+	return &ast.GenDecl{
+		Tok: token.TYPE,
+		Doc: Doc(a.Description),
+		Specs: []ast.Spec{
+			&ast.TypeSpec{
+				Name: &ast.Ident{Name: a.Name},
+				Type: a.Type,
+			},
+		},
+	}
+}
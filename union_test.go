@@ -0,0 +1,48 @@
+package apigen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"os"
+)
+
+func ExampleUnionDecls() {
+	u := &Union{
+		Name: "NumberOrString",
+		Alternatives: []*Alternative{
+			&Alternative{
+				Name:   "Number",
+				Type:   &ast.Ident{Name: "float64"},
+				TypeOf: "number",
+				Convert: func(j ast.Expr) ast.Expr {
+					return &ast.CallExpr{Fun: &ast.Ident{Name: "Number"}, Args: []ast.Expr{FloatConverter(j)}}
+				},
+			},
+			&Alternative{
+				Name:   "String",
+				Type:   &ast.Ident{Name: "string"},
+				TypeOf: "string",
+				Convert: func(j ast.Expr) ast.Expr {
+					return &ast.CallExpr{Fun: &ast.Ident{Name: "String"}, Args: []ast.Expr{StringConverter(j)}}
+				},
+			},
+		},
+	}
+	decls := UnionDecls(u)
+	printer.Fprint(os.Stdout, token.NewFileSet(), decls[len(decls)-1]) // the converter
+	fmt.Println()
+	printer.Fprint(os.Stdout, token.NewFileSet(), AlternativeDecl(u.Alternatives[0]))
+	//Output:
+	// func newNumberOrString(j *js.Object) NumberOrString {
+	// 	switch jsTypeOf(j) {
+	// 	case "number":
+	// 		return Number(j.Float())
+	// 	case "string":
+	// 		return String(j.String())
+	// 	}
+	// 	return nil
+	// }
+	// type Number float64
+}
@@ -0,0 +1,245 @@
+package apigen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+//RewriteWasm rewrites the gopherjs bodies of 'file' for syscall/js:
+//
+//	js.Global                     js.Global()
+//	x.Interface()                 x
+//	x.Int64()                     int64(x.Float())
+//	func(j Object) {...}          js.FuncOf(func(this js.Value, args []js.Value) interface{} {...})
+//	x.Call("m", t) (t a Type)     x.Call("m", t.Object)
+//	jsTypeOf(j)                   j.Type().String()
+//
+// syscall/js ignores the js struct tags: the properties are copied by the wrappers (see CopyProperties).
+// The js functions created for go funcs are never released, the funcs creating them document it.
+func RewriteWasm(api *Api, file *ast.File) {
+	types := make(map[string]bool)
+	for _, t := range api.Types {
+		types[t.Name] = true
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if s, ok := n.(*ast.SelectorExpr); ok && isJSGlobal(s.X) {
+			s.X = &ast.CallExpr{Fun: s.X}
+		}
+		return true
+	})
+	rewriteExprs(file, wasmExpr)
+
+	for _, d := range file.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok || fd.Body == nil {
+			continue
+		}
+		unwrapArgs(fd, types)
+		if hasFuncOf(fd.Body) {
+			fd.Doc = AppendDoc(fd.Doc, funcOfLeak)
+		}
+	}
+	typeOfBody(file, StringConverter(&ast.CallExpr{Fun: &ast.SelectorExpr{X: &ast.Ident{Name: "j"}, Sel: &ast.Ident{Name: "Type"}}}))
+	CopyProperties(api, file)
+}
+
+//funcOfLeak documents the funcs creating js functions
+const funcOfLeak = "Each call creates a js function for the go func, that is never released (see js.Func.Release): " +
+	"the go func stays reachable as long as the program runs."
+
+//hasFuncOf returns true if 'body' creates a js function (js.FuncOf)
+func hasFuncOf(body *ast.BlockStmt) (found bool) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "FuncOf" && isIdent(sel.X, "js") {
+				found = true
+			}
+		}
+		return !found
+	})
+	return
+}
+
+//CopyProperties makes the wrappers of the types copy the properties of the js object into the
+// struct fields, for the targets that ignore the js struct tags.
+//
+// only the properties of a basic or Object type are copied, the others are left empty.
+func CopyProperties(api *Api, file *ast.File) {
+	for _, t := range api.Types {
+		if fd := funcDecl(file, CtorName(t)); fd != nil && len(t.Properties) > 0 {
+			copyProperties(t, fd)
+		}
+	}
+}
+
+//isJSGlobal returns true if 'e' is js.Global
+func isJSGlobal(e ast.Expr) bool {
+	sel, ok := e.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Global" && isIdent(sel.X, "js")
+}
+
+//wasmExpr rewrites the gopherjs only conversions, and the go funcs passed to js
+func wasmExpr(e ast.Expr) ast.Expr {
+	switch e := e.(type) {
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok || len(e.Args) > 0 {
+			return e
+		}
+		switch sel.Sel.Name {
+		case "Interface":
+			return sel.X
+		case "Int64", "Uint64":
+			conv := "int64"
+			if sel.Sel.Name == "Uint64" {
+				conv = "uint64"
+			}
+			return &ast.CallExpr{Fun: &ast.Ident{Name: conv}, Args: []ast.Expr{FloatConverter(sel.X)}}
+		}
+	case *ast.FuncLit:
+		if fn := funcOf(e); fn != nil {
+			return fn
+		}
+	}
+	return e
+}
+
+//funcOf wraps a go func of js objects into js.FuncOf (nil if it's not such a func)
+func funcOf(f *ast.FuncLit) ast.Expr {
+	if f.Type.Results != nil && len(f.Type.Results.List) > 0 {
+		return nil
+	}
+	var body []ast.Stmt
+	i := 0
+	for _, p := range f.Type.Params.List {
+		if !isObject(p.Type) {
+			return nil
+		}
+		for _, name := range p.Names {
+			body = append(body, &ast.AssignStmt{
+				Lhs: []ast.Expr{&ast.Ident{Name: name.Name}},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{&ast.IndexExpr{
+					X:     &ast.Ident{Name: "args"},
+					Index: &ast.BasicLit{Kind: token.INT, Value: fmt.Sprint(i)},
+				}},
+			})
+			i++
+		}
+	}
+	body = append(body, f.Body.List...)
+	body = append(body, &ast.ReturnStmt{Results: []ast.Expr{&ast.Ident{Name: "nil"}}})
+
+	value := &ast.SelectorExpr{X: &ast.Ident{Name: "js"}, Sel: &ast.Ident{Name: "Value"}}
+	return &ast.CallExpr{
+		Fun: &ast.SelectorExpr{X: &ast.Ident{Name: "js"}, Sel: &ast.Ident{Name: "FuncOf"}},
+		Args: []ast.Expr{&ast.FuncLit{
+			Type: &ast.FuncType{
+				Params: &ast.FieldList{List: []*ast.Field{
+					{Names: []*ast.Ident{{Name: "this"}}, Type: value},
+					{Names: []*ast.Ident{{Name: "args"}}, Type: &ast.ArrayType{Elt: value}},
+				}},
+				Results: &ast.FieldList{List: []*ast.Field{{Type: EmptyInterface()}}},
+			},
+			Body: &ast.BlockStmt{List: body},
+		}},
+	}
+}
+
+//unwrapArgs passes the js object of the params of a generated type (syscall/js cannot convert a struct)
+func unwrapArgs(fd *ast.FuncDecl, types map[string]bool) {
+	params := make(map[string]bool)
+	for _, p := range fd.Type.Params.List {
+		if id, ok := p.Type.(*ast.Ident); ok && types[id.Name] {
+			for _, name := range p.Names {
+				params[name.Name] = true
+			}
+		}
+	}
+	if len(params) == 0 {
+		return
+	}
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); !ok || (sel.Sel.Name != "Call" && sel.Sel.Name != "New" && sel.Sel.Name != "Invoke") {
+			return true
+		}
+		for i, arg := range call.Args {
+			if id, ok := arg.(*ast.Ident); ok && params[id.Name] {
+				call.Args[i] = &ast.SelectorExpr{X: id, Sel: &ast.Ident{Name: ObjectAlias}}
+			}
+		}
+		return true
+	})
+}
+
+//funcDecl returns the func called 'name' declared in 'file' (nil if there is none)
+func funcDecl(file *ast.File, name string) *ast.FuncDecl {
+	for _, d := range file.Decls {
+		if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.Name == name {
+			return fd
+		}
+	}
+	return nil
+}
+
+//copyProperties adds the properties of 't' to the struct literal returned by its wrapper 'fd'
+func copyProperties(t *Type, fd *ast.FuncDecl) {
+	if len(fd.Body.List) != 1 || len(fd.Type.Params.List) != 1 {
+		return
+	}
+	ret, ok := fd.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return
+	}
+	lit, ok := ret.Results[0].(*ast.CompositeLit)
+	if !ok {
+		return
+	}
+	j := fd.Type.Params.List[0].Names[0].Name
+	for _, p := range t.Properties {
+		convert := propertyConverter(p.Type)
+		if convert == nil {
+			continue
+		}
+		get := &ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: &ast.Ident{Name: j}, Sel: &ast.Ident{Name: "Get"}},
+			Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", p.JS)}},
+		}
+		lit.Elts = append(lit.Elts, &ast.KeyValueExpr{Key: &ast.Ident{Name: p.Name}, Value: convert(get)})
+	}
+}
+
+//propertyConverter returns the conversion of a js value to the property type 'typ' (nil if there is none)
+func propertyConverter(typ ast.Expr) func(ast.Expr) ast.Expr {
+	if isObject(typ) {
+		return IdentityConverter
+	}
+	if it, ok := typ.(*ast.InterfaceType); ok && len(it.Methods.List) == 0 {
+		return IdentityConverter
+	}
+	id, ok := typ.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	switch id.Name {
+	case "bool":
+		return BoolConverter
+	case "string":
+		return StringConverter
+	case "int":
+		return IntConverter
+	case "float64":
+		return FloatConverter
+	case "int64", "uint64":
+		return func(e ast.Expr) ast.Expr {
+			return &ast.CallExpr{Fun: &ast.Ident{Name: id.Name}, Args: []ast.Expr{FloatConverter(e)}}
+		}
+	}
+	return nil
+}